@@ -756,6 +756,21 @@ func (de DirEntry) Digest() string {
 	return f[4]
 }
 
+// ModTime returns the modification time of a regular file, which is
+// listed to the second. It reports false if the time isn't included,
+// as it isn't for directories.
+func (de DirEntry) ModTime() (time.Time, bool) {
+	f := strings.Split(de.Line, "\t")
+	if len(f) < 4 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, f[3])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
 // ListDirOpts are options for Client.ListDir.
 type ListDirOpts struct {
 	// Recursive controls whether the directory is listed
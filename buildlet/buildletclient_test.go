@@ -15,6 +15,7 @@ import (
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestConnectSSHTLS(t *testing.T) {
@@ -225,3 +226,22 @@ func (c deadlineOnDemandContext) Err() error {
 		return context.DeadlineExceeded
 	}
 }
+
+func TestDirEntryModTime(t *testing.T) {
+	testCases := []struct {
+		line   string
+		want   time.Time
+		wantOK bool
+	}{
+		{line: "-rw-r--r--\tgo/VERSION\t8\t2023-06-01T12:34:56Z", want: time.Date(2023, 6, 1, 12, 34, 56, 0, time.UTC), wantOK: true},
+		{line: "-rw-r--r--\tgo/VERSION\t8\t2023-06-01T12:34:56Z\tda39a3ee5e6b4b0d3255bfef95601890afd80709", want: time.Date(2023, 6, 1, 12, 34, 56, 0, time.UTC), wantOK: true},
+		{line: "drwxr-xr-x\tgo/"},
+		{line: "-rw-r--r--\tgo/VERSION\t8\tyesterday"},
+	}
+	for _, tc := range testCases {
+		got, ok := DirEntry{Line: tc.line}.ModTime()
+		if ok != tc.wantOK || !got.Equal(tc.want) {
+			t.Errorf("DirEntry{%q}.ModTime() = %v, %t; want %v, %t", tc.line, got, ok, tc.want, tc.wantOK)
+		}
+	}
+}
@@ -113,6 +113,8 @@ var (
 	devEnableGCE  = flag.Bool("dev_gce", false, "Whether or not to enable the GCE pool when in dev mode. The pool is enabled by default in prod mode.")
 	devEnableEC2  = flag.Bool("dev_ec2", false, "Whether or not to enable the EC2 pool when in dev mode. The pool is enabled by default in prod mode.")
 	sshAddr       = flag.String("ssh_addr", ":2222", "Address the gomote SSH server should listen on")
+
+	gomoteUploadBuckets = flag.String("gomote_upload_buckets", "", "Comma-separated list of GCS buckets, in addition to the environment's transfer bucket, which gomote clients may upload files to.")
 )
 
 // LOCK ORDER:
@@ -347,7 +349,11 @@ func main() {
 	dashV2 := &builddash.Handler{Datastore: gce.GoDSClient(), Maintner: maintnerClient}
 	gs := &gRPCServer{dashboardURL: "https://build.golang.org"}
 	setSessionPool(sp)
-	gomoteServer := gomote.New(sp, sched, sshCA, gomoteBucket, mustStorageClient())
+	var uploadBuckets []string
+	if *gomoteUploadBuckets != "" {
+		uploadBuckets = strings.Split(*gomoteUploadBuckets, ",")
+	}
+	gomoteServer := gomote.New(sp, sched, sshCA, gomoteBucket, mustStorageClient(), uploadBuckets...)
 	protos.RegisterCoordinatorServer(grpcServer, gs)
 	gomoteprotos.RegisterGomoteServiceServer(grpcServer, gomoteServer)
 	mux.HandleFunc("/", grpcHandlerFunc(grpcServer, handleStatus)) // Serve a status page at farmer.golang.org.
@@ -0,0 +1,84 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// checksumAlgo is a digest algorithm for the checksums of uploads.
+type checksumAlgo struct {
+	name string
+	new  func() hash.Hash
+}
+
+// checksumAlgos are the algorithms -checksum-algo accepts. SHA-1 is only
+// for systems which still require it.
+var checksumAlgos = map[string]checksumAlgo{
+	"sha1":   {"sha1", sha1.New},
+	"sha256": {"sha256", sha256.New},
+	"sha512": {"sha512", sha512.New},
+}
+
+// parseChecksumAlgo returns the algorithm named s.
+func parseChecksumAlgo(s string) (*checksumAlgo, error) {
+	algo, ok := checksumAlgos[s]
+	if !ok {
+		var names []string
+		for name := range checksumAlgos {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown checksum algorithm %q; want one of %s", s, strings.Join(names, ", "))
+	}
+	return &algo, nil
+}
+
+// checksumManifest is a local record of the digests of files uploaded
+// to instances. Each line is of the form "<digest>  <destination>  <instance>",
+// where the digest is hex-encoded SHA-256, or "<algorithm>:<hex>" if
+// -checksum-algo was given.
+type checksumManifest struct {
+	mu sync.Mutex // guards writes to f
+	f  *os.File
+}
+
+// openChecksumManifest opens the manifest at path for writing.
+// If appendTo is true, records are appended to any existing file.
+// Otherwise the file is truncated.
+func openChecksumManifest(path string, appendTo bool) (*checksumManifest, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendTo {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+	f, err := os.OpenFile(path, flags, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening checksum file: %w", err)
+	}
+	return &checksumManifest{f: f}, nil
+}
+
+// record adds a line for a successful upload of a file with the formatted
+// digest sum to dst on inst.
+func (m *checksumManifest) record(sum, dst, inst string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, err := fmt.Fprintf(m.f, "%s  %s  %s\n", sum, dst, inst); err != nil {
+		return fmt.Errorf("writing checksum file: %w", err)
+	}
+	return nil
+}
+
+func (m *checksumManifest) Close() error {
+	return m.f.Close()
+}
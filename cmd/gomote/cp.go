@@ -0,0 +1,89 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/sync/errgroup"
+)
+
+// cp copies a directory from one instance to others. The directory is
+// tarred up into the transfer bucket by the server and each destination
+// instance fetches it from there, so nothing is downloaded locally.
+func cp(args []string) error {
+	fs := flag.NewFlagSet("cp", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "cp usage: gomote cp [cp-opts] <src-instance>:<dir> [<dst-instance>:]<dir>")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Copies the contents of a directory, relative to the source instance's work dir,")
+		fmt.Fprintln(os.Stderr, "into a directory relative to the destination instance's work dir.")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Destination instance name is optional if a group is specified, in which case")
+		fmt.Fprintln(os.Stderr, "the directory is copied to every other instance in the group.")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+	var jobs int
+	registerJobsFlag(fs, &jobs)
+	fs.Parse(args)
+	if fs.NArg() != 2 {
+		fs.Usage()
+	}
+
+	srcInst, srcDir, ok := strings.Cut(fs.Arg(0), ":")
+	if !ok || srcInst == "" {
+		return fmt.Errorf("source %q is not of the form <instance>:<dir>", fs.Arg(0))
+	}
+	var dstSet []string
+	var dstDir string
+	if inst, dir, ok := strings.Cut(fs.Arg(1), ":"); ok && inst != "" {
+		dstSet = []string{inst}
+		dstDir = dir
+	} else {
+		if activeGroup == nil {
+			fmt.Fprintln(os.Stderr, "no active group found; need an active group without a destination instance")
+			fs.Usage()
+		}
+		for _, inst := range activeGroup.Instances {
+			if inst != srcInst {
+				dstSet = append(dstSet, inst)
+			}
+		}
+		if len(dstSet) == 0 {
+			return fmt.Errorf("no instances in group %q besides %q", activeGroup.Name, srcInst)
+		}
+		dstDir = strings.TrimPrefix(fs.Arg(1), ":")
+	}
+
+	ctx := context.Background()
+	client := gomoteServerClient(ctx)
+	fmt.Fprintf(os.Stderr, "# Reading %q from %q...\n", srcDir, srcInst)
+	resp, err := client.ReadTGZToURL(ctx, &protos.ReadTGZToURLRequest{
+		GomoteId:  srcInst,
+		Directory: srcDir,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to retrieve tgz URL: %w", err)
+	}
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(putJobs(jobs, len(dstSet)))
+	for _, inst := range dstSet {
+		inst := inst
+		eg.Go(func() error {
+			fmt.Fprintf(os.Stderr, "# Writing to %q...\n", inst)
+			if err := doPutTarURL(ctx, inst, dstDir, resp.GetUrl(), &putOptions{}); err != nil {
+				return fmt.Errorf("%s: %w", inst, err)
+			}
+			return nil
+		})
+	}
+	return eg.Wait()
+}
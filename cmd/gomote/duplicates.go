@@ -0,0 +1,128 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// checkDuplicates reports destinations which a put would write more than
+// once: instances listed more than once in insts, and paths that more than
+// one entry of the local tarballs in sources extracts to, including those
+// which a later source overwrites. Sources which aren't local, such as
+// URLs, can't be inspected. Each is a warning, or with -strict, an error.
+func (o *putOptions) checkDuplicates(insts []string, sources []*tarSource) error {
+	var dups []string
+	seen := make(map[string]int)
+	for _, inst := range insts {
+		seen[inst]++
+		if seen[inst] == 2 {
+			dups = append(dups, fmt.Sprintf("instance %q is written to more than once", inst))
+		}
+	}
+	var entries []tarEntry
+	for n, s := range sources {
+		if s.open == nil {
+			continue
+		}
+		rc, err := s.open()
+		if err != nil {
+			return err
+		}
+		names, err := tarGzNames(rc)
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("inspecting %s: %w", s.src, err)
+		}
+		for i, name := range names {
+			entries = append(entries, tarEntry{
+				path:  path.Join(s.dir, name),
+				src:   s.src,
+				n:     n,
+				index: i + 1,
+			})
+		}
+	}
+	dups = append(dups, entryDuplicates(entries)...)
+	if len(dups) == 0 {
+		return nil
+	}
+	if o.strictDuplicates {
+		return fmt.Errorf("duplicate destinations:\n\t%s", strings.Join(dups, "\n\t"))
+	}
+	for _, dup := range dups {
+		fmt.Fprintf(os.Stderr, "# warning: %s\n", dup)
+	}
+	return nil
+}
+
+// tarEntry is an entry of a tarball extracted on an instance.
+type tarEntry struct {
+	path  string // where it's extracted, relative to the work directory
+	src   string // the source of the tarball
+	n     int    // the position of the source among those extracted
+	index int    // its position in the tarball, starting at 1
+}
+
+// entryDuplicates describes each path that more than one of entries,
+// which are in the order they're extracted, extracts to, in the order
+// they first appear. The last such entry wins.
+func entryDuplicates(entries []tarEntry) []string {
+	var paths []string
+	byPath := make(map[string][]tarEntry)
+	for _, e := range entries {
+		if byPath[e.path] == nil {
+			paths = append(paths, e.path)
+		}
+		byPath[e.path] = append(byPath[e.path], e)
+	}
+	var dups []string
+	for _, p := range paths {
+		es := byPath[p]
+		if len(es) < 2 {
+			continue
+		}
+		var srcs []string
+		for i, e := range es {
+			if i == 0 || es[i-1].n != e.n {
+				srcs = append(srcs, e.src)
+			}
+		}
+		last := es[len(es)-1]
+		if len(srcs) == 1 {
+			dups = append(dups, fmt.Sprintf("%s appears %d times in the tarball; entry %d wins", p, len(es), last.index))
+		} else {
+			dups = append(dups, fmt.Sprintf("%s is extracted %d times, from %s; entry %d of %s wins", p, len(es), strings.Join(srcs, ", "), last.index, last.src))
+		}
+	}
+	return dups
+}
+
+// tarGzNames returns the cleaned names of the entries of the .tar.gz read
+// from r, in order.
+func tarGzNames(r io.Reader) ([]string, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(zr)
+	var names []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, path.Clean(h.Name))
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntryDuplicates(t *testing.T) {
+	testCases := []struct {
+		desc    string
+		entries []tarEntry
+		want    []string
+	}{
+		{
+			desc: "no duplicates",
+			entries: []tarEntry{
+				{path: "a", src: "x.tar.gz", index: 1},
+				{path: "b", src: "x.tar.gz", index: 2},
+			},
+		},
+		{
+			desc: "within a tarball",
+			entries: []tarEntry{
+				{path: "a", src: "x.tar.gz", index: 1},
+				{path: "b", src: "x.tar.gz", index: 2},
+				{path: "a", src: "x.tar.gz", index: 3},
+			},
+			want: []string{"a appears 2 times in the tarball; entry 3 wins"},
+		},
+		{
+			desc: "overlaid by a later source",
+			entries: []tarEntry{
+				{path: "go/VERSION", src: "base.tar.gz", n: 0, index: 1},
+				{path: "go/bin", src: "base.tar.gz", n: 0, index: 2},
+				{path: "go/VERSION", src: "patch.tar.gz", n: 1, index: 4},
+			},
+			want: []string{"go/VERSION is extracted 2 times, from base.tar.gz, patch.tar.gz; entry 4 of patch.tar.gz wins"},
+		},
+		{
+			desc: "same tarball listed twice",
+			entries: []tarEntry{
+				{path: "a", src: "x.tar.gz", n: 0, index: 1},
+				{path: "a", src: "x.tar.gz", n: 1, index: 1},
+			},
+			want: []string{"a is extracted 2 times, from x.tar.gz, x.tar.gz; entry 1 of x.tar.gz wins"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			if got := entryDuplicates(tc.entries); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("entryDuplicates() = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/build/internal/gomote/sealed"
+)
+
+// encryptionKeyEnv is the environment variable holding the key used by
+// the -encrypt flag. It's read from the environment rather than a flag
+// so that it doesn't show up in process listings or shell history.
+const encryptionKeyEnv = "GOMOTE_ENCRYPTION_KEY"
+
+// loadEncryptionKey returns the key in $GOMOTE_ENCRYPTION_KEY, which holds
+// it as 64 hex digits. Errors never include the key.
+func loadEncryptionKey() ([]byte, error) {
+	v := os.Getenv(encryptionKeyEnv)
+	if v == "" {
+		return nil, fmt.Errorf("-encrypt requires a key in $%s", encryptionKeyEnv)
+	}
+	key, err := hex.DecodeString(v)
+	if err != nil || len(key) != sealed.KeySize {
+		return nil, fmt.Errorf("$%s must be 64 hex digits", encryptionKeyEnv)
+	}
+	return key, nil
+}
+
+// setDecryptionKey sets encryptionKey as the key the server decrypts
+// uploads written to inst with, unless it's already been set for inst.
+// The key is sent once per instance rather than with every write.
+func (o *putOptions) setDecryptionKey(ctx context.Context, client protos.GomoteServiceClient, inst string) error {
+	if _, done := o.keyedInstances.Load(inst); done {
+		return nil
+	}
+	if _, err := client.SetDecryptionKey(ctx, &protos.SetDecryptionKeyRequest{GomoteId: inst, Key: o.encryptionKey}); err != nil {
+		return fmt.Errorf("unable to set the decryption key of %s: %w", inst, err)
+	}
+	o.keyedInstances.Store(inst, true)
+	return nil
+}
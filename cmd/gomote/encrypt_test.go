@@ -0,0 +1,66 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/build/internal/gomote/sealed"
+	"google.golang.org/grpc"
+)
+
+// fakeKeyClient records the decryption keys set for instances.
+type fakeKeyClient struct {
+	protos.GomoteServiceClient
+	keyed []string
+}
+
+func (c *fakeKeyClient) SetDecryptionKey(ctx context.Context, in *protos.SetDecryptionKeyRequest, opts ...grpc.CallOption) (*protos.SetDecryptionKeyResponse, error) {
+	c.keyed = append(c.keyed, in.GetGomoteId())
+	return &protos.SetDecryptionKeyResponse{}, nil
+}
+
+func TestSetDecryptionKeyOncePerInstance(t *testing.T) {
+	client := &fakeKeyClient{}
+	o := &putOptions{encryptionKey: bytes.Repeat([]byte{0x42}, sealed.KeySize)}
+	for _, inst := range []string{"a", "b", "a", "a", "b"} {
+		if err := o.setDecryptionKey(context.Background(), client, inst); err != nil {
+			t.Fatalf("setDecryptionKey(%s) = %s", inst, err)
+		}
+	}
+	if want := []string{"a", "b"}; !reflect.DeepEqual(client.keyed, want) {
+		t.Errorf("keys set for %q; want %q", client.keyed, want)
+	}
+}
+
+func TestUploadBodySealed(t *testing.T) {
+	const content = "secret fixture"
+	key := bytes.Repeat([]byte{0x42}, sealed.KeySize)
+	o := &putOptions{encryptionKey: key}
+	body, err := o.uploadBody(strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("uploadBody() = %s", err)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(content)) {
+		t.Errorf("uploadBody() holds the contents in the clear")
+	}
+	r, err := sealed.NewOpener(bytes.NewReader(data), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := io.ReadAll(r); err != nil || string(got) != content {
+		t.Errorf("opened uploadBody() = %q, %v; want %q, nil", got, err, content)
+	}
+}
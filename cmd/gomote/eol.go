@@ -0,0 +1,91 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// textSniffLen is how much of a file is inspected to decide whether it's
+// text. As in git, a file is binary if this much of it contains a NUL.
+const textSniffLen = 8000
+
+// eolConverter normalizes the line endings of the text files packaged
+// from a directory source.
+type eolConverter struct {
+	crlf       bool            // whether lines end in CRLF, rather than LF
+	textExts   map[string]bool // extensions, such as ".bat", of files always treated as text
+	binaryExts map[string]bool // extensions of files never treated as text
+}
+
+// parseEOL parses the value of the -eol flag, reporting whether it's crlf.
+func parseEOL(s string) (crlf bool, err error) {
+	switch s {
+	case "crlf":
+		return true, nil
+	case "lf":
+		return false, nil
+	}
+	return false, fmt.Errorf("-eol must be crlf or lf, not %q", s)
+}
+
+// parseExtList parses a comma-separated list of file extensions, with or
+// without leading dots, into a set of lower-case extensions with dots.
+func parseExtList(s string) map[string]bool {
+	exts := make(map[string]bool)
+	for _, ext := range strings.Split(s, ",") {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext == "" {
+			continue
+		}
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		exts[ext] = true
+	}
+	return exts
+}
+
+// textContent returns the contents of the file at path with normalized
+// line endings, and true, if it's a text file. Otherwise it reports false
+// without reading the rest of the file.
+func (c *eolConverter) textContent(path string) ([]byte, bool, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	if c.binaryExts[ext] {
+		return nil, false, nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	head := make([]byte, textSniffLen)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, false, err
+	}
+	if !c.textExts[ext] && bytes.IndexByte(head[:n], 0) >= 0 {
+		return nil, false, nil
+	}
+	rest, err := io.ReadAll(f)
+	if err != nil {
+		return nil, false, err
+	}
+	return c.convert(append(head[:n], rest...)), true, nil
+}
+
+// convert returns data with each line ending in LF or CRLF, as configured.
+func (c *eolConverter) convert(data []byte) []byte {
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	if c.crlf {
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+	return data
+}
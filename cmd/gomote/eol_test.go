@@ -0,0 +1,106 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEOLConvert(t *testing.T) {
+	testCases := []struct {
+		in       string
+		crlf, lf string
+	}{
+		{"", "", ""},
+		{"a\nb\n", "a\r\nb\r\n", "a\nb\n"},
+		{"a\r\nb\r\n", "a\r\nb\r\n", "a\nb\n"},
+		{"a\r\nb\nc", "a\r\nb\r\nc", "a\nb\nc"},
+		// A lone CR isn't a line ending, so it's left alone.
+		{"a\rb\n", "a\rb\r\n", "a\rb\n"},
+		{"a\r\r\n", "a\r\r\n", "a\r\n"},
+		{"\n\n", "\r\n\r\n", "\n\n"},
+	}
+	for _, tc := range testCases {
+		crlf := &eolConverter{crlf: true}
+		if got := string(crlf.convert([]byte(tc.in))); got != tc.crlf {
+			t.Errorf("crlf convert(%q) = %q; want %q", tc.in, got, tc.crlf)
+		}
+		lf := &eolConverter{}
+		if got := string(lf.convert([]byte(tc.in))); got != tc.lf {
+			t.Errorf("lf convert(%q) = %q; want %q", tc.in, got, tc.lf)
+		}
+	}
+}
+
+func TestEOLTextContent(t *testing.T) {
+	// A CRLF split across the end of the sniffed prefix.
+	split := strings.Repeat("x", textSniffLen-1) + "\r\nend\n"
+	// A NUL after the sniffed prefix doesn't make a file binary.
+	lateNUL := strings.Repeat("x", textSniffLen) + "\x00\n"
+	testCases := []struct {
+		name, data string
+		want       string // or "" if it's binary
+	}{
+		{"a.txt", "a\nb\n", "a\r\nb\r\n"},
+		{"a.txt", "a\r\nb\r\n", "a\r\nb\r\n"},
+		{"a.go", "package a\n", "package a\r\n"},
+		{"noext", "x\n", "x\r\n"},
+		{"a.bin", "a\x00b\n", ""},
+		{"a.txt", "a\x00b\n", ""},
+		{"a.bat", "a\x00b\n", "a\x00b\r\n"},
+		{"a.BAT", "a\x00b\n", "a\x00b\r\n"},
+		{"a.dat", "looks like text\n", ""},
+		{"a.DAT", "looks like text\n", ""},
+		{"split.txt", split, strings.Repeat("x", textSniffLen-1) + "\r\nend\r\n"},
+		{"late.txt", lateNUL, strings.Repeat("x", textSniffLen) + "\x00\r\n"},
+	}
+	dir := t.TempDir()
+	c := &eolConverter{crlf: true, textExts: parseExtList("bat, .cmd"), binaryExts: parseExtList(".DAT")}
+	for _, tc := range testCases {
+		path := filepath.Join(dir, tc.name)
+		if err := os.WriteFile(path, []byte(tc.data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		got, ok, err := c.textContent(path)
+		if err != nil {
+			t.Fatalf("textContent(%s) = %v", tc.name, err)
+		}
+		if ok != (tc.want != "") {
+			t.Errorf("textContent(%s) reported text = %t; want %t", tc.name, ok, tc.want != "")
+			continue
+		}
+		if ok && string(got) != tc.want {
+			t.Errorf("textContent(%s) = %q; want %q", tc.name, got, tc.want)
+		}
+	}
+	if _, _, err := c.textContent(filepath.Join(dir, "missing")); err == nil {
+		t.Errorf("textContent(missing) = nil error; want error")
+	}
+}
+
+func TestParseEOL(t *testing.T) {
+	for s, want := range map[string]bool{"crlf": true, "lf": false} {
+		if got, err := parseEOL(s); err != nil || got != want {
+			t.Errorf("parseEOL(%q) = %t, %v; want %t, nil", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "CRLF", "cr", "windows"} {
+		if _, err := parseEOL(s); err == nil {
+			t.Errorf("parseEOL(%q) = _, nil; want error", s)
+		}
+	}
+}
+
+func TestParseExtList(t *testing.T) {
+	got := parseExtList(" .BAT,cmd,, ps1 ,")
+	want := map[string]bool{".bat": true, ".cmd": true, ".ps1": true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseExtList() = %v; want %v", got, want)
+	}
+}
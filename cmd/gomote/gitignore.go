@@ -0,0 +1,193 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignorePattern is a pattern in .gitignore syntax.
+type ignorePattern struct {
+	base    string   // directory of the .gitignore file, relative to the root; "" for the root
+	elems   []string // pattern split into path elements, where "**" matches any number of them
+	negate  bool     // re-includes matching paths
+	dirOnly bool     // only matches directories
+}
+
+// parseIgnorePattern parses a line of a .gitignore file in the directory
+// base, relative to the root being packaged. It reports false for blank
+// lines and comments.
+//
+// As in git, a pattern with a slash other than a trailing one is anchored
+// to base, and otherwise matches a name at any depth below it. A trailing
+// slash matches only directories, and a leading ! negates the pattern.
+func parseIgnorePattern(base, line string) (ignorePattern, bool) {
+	line = strings.TrimSuffix(line, "\r")
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if line == "" || line[0] == '#' {
+		return ignorePattern{}, false
+	}
+	p := ignorePattern{base: base}
+	if line[0] == '!' {
+		p.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		p.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+	if line == "" {
+		return ignorePattern{}, false
+	}
+	p.elems = strings.Split(line, "/")
+	if !anchored {
+		p.elems = append([]string{"**"}, p.elems...)
+	}
+	return p, true
+}
+
+// match reports whether the pattern matches rel, a slash-separated path
+// relative to the root.
+func (p ignorePattern) match(rel string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	if p.base != "" {
+		if !strings.HasPrefix(rel, p.base+"/") {
+			return false
+		}
+		rel = rel[len(p.base)+1:]
+	}
+	return matchElems(p.elems, strings.Split(rel, "/"))
+}
+
+// matchElems reports whether the pattern elements pats match the path
+// elements names. A trailing "**" matches one or more elements, and any
+// other "**" matches zero or more.
+func matchElems(pats, names []string) bool {
+	if len(pats) == 0 {
+		return len(names) == 0
+	}
+	if pats[0] == "**" {
+		if len(pats) == 1 {
+			return len(names) > 0
+		}
+		for i := 0; i <= len(names); i++ {
+			if matchElems(pats[1:], names[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(names) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pats[0], names[0]); !ok {
+		return false
+	}
+	return matchElems(pats[1:], names[1:])
+}
+
+// ignoreList is a list of patterns in which the last one matching a path
+// decides whether it's ignored, so that patterns from deeper .gitignore
+// files, which are added later, take precedence.
+type ignoreList []ignorePattern
+
+// ignored reports whether rel, a slash-separated path relative to the
+// root, is ignored.
+func (l ignoreList) ignored(rel string, isDir bool) bool {
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i].match(rel, isDir) {
+			return !l[i].negate
+		}
+	}
+	return false
+}
+
+// loadGitignore returns l with the patterns of the .gitignore file in dir,
+// if any, added. rel is dir relative to the root, or "" for the root.
+func (l ignoreList) loadGitignore(dir, rel string) (ignoreList, error) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if p, ok := parseIgnorePattern(rel, sc.Text()); ok {
+			l = append(l, p)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", f.Name(), err)
+	}
+	return l, nil
+}
+
+// parseExcludePattern parses an -exclude pattern, which has .gitignore
+// syntax, relative to the root of a directory source, but can't be negated.
+func parseExcludePattern(s string) (ignorePattern, error) {
+	if strings.HasPrefix(s, "!") {
+		return ignorePattern{}, fmt.Errorf("-exclude pattern %q can't be negated", s)
+	}
+	p, ok := parseIgnorePattern("", s)
+	if !ok {
+		return ignorePattern{}, fmt.Errorf("-exclude pattern %q is empty or a comment", s)
+	}
+	return p, nil
+}
+
+// dirFilter selects the paths packaged from a directory source.
+type dirFilter struct {
+	excludes     ignoreList // from -exclude, which win over .gitignore files
+	useGitignore bool       // whether .gitignore files in the tree are honored
+}
+
+// walker returns a function which reports whether a path in a walk of
+// a directory tree is skipped. The walk must visit each directory before
+// its contents, starting with the root itself as ".". A nil filter skips
+// nothing.
+func (f *dirFilter) walker() func(path, rel string, isDir bool) (bool, error) {
+	if f == nil {
+		return func(string, string, bool) (bool, error) { return false, nil }
+	}
+	var gitignores ignoreList
+	return func(path, rel string, isDir bool) (bool, error) {
+		if rel != "." {
+			rel = filepath.ToSlash(rel)
+			if f.excludes.ignored(rel, isDir) {
+				return true, nil
+			}
+			if f.useGitignore && (rel == ".git" || gitignores.ignored(rel, isDir)) {
+				return true, nil
+			}
+		}
+		if isDir && f.useGitignore {
+			base := rel
+			if base == "." {
+				base = ""
+			}
+			var err error
+			if gitignores, err = gitignores.loadGitignore(path, base); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+}
@@ -0,0 +1,214 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestIgnorePatternMatch(t *testing.T) {
+	testCases := []struct {
+		base, pattern string
+		path          string
+		isDir         bool
+		want          bool
+	}{
+		// Unanchored patterns match at any depth.
+		{"", "*.o", "a.o", false, true},
+		{"", "*.o", "x/y/a.o", false, true},
+		{"", "*.o", "a.go", false, false},
+		{"", "build", "x/build", true, true},
+		{"", "build", "x/build/y", false, false},
+		// Anchored patterns match only relative to their directory.
+		{"", "/build", "build", true, true},
+		{"", "/build", "x/build", true, false},
+		{"", "doc/*.html", "doc/a.html", false, true},
+		{"", "doc/*.html", "x/doc/a.html", false, false},
+		{"", "doc/*.html", "doc/x/a.html", false, false},
+		// Directory-only patterns.
+		{"", "out/", "out", true, true},
+		{"", "out/", "out", false, false},
+		{"", "out/", "x/out", true, true},
+		// **.
+		{"", "**/testdata", "testdata", true, true},
+		{"", "**/testdata", "a/b/testdata", true, true},
+		{"", "a/**/z", "a/z", false, true},
+		{"", "a/**/z", "a/b/c/z", false, true},
+		{"", "a/**/z", "b/a/z", false, false},
+		{"", "a/**", "a/b", false, true},
+		{"", "a/**", "a/b/c", false, true},
+		{"", "a/**", "a", true, false},
+		// Patterns from a nested .gitignore apply below its directory.
+		{"sub", "*.log", "sub/a.log", false, true},
+		{"sub", "*.log", "sub/x/a.log", false, true},
+		{"sub", "*.log", "a.log", false, false},
+		{"sub", "/gen", "sub/gen", true, true},
+		{"sub", "/gen", "sub/x/gen", true, false},
+		{"sub", "/gen", "subx/gen", true, false},
+		// Escapes and trailing spaces.
+		{"", `\#notes`, "#notes", false, true},
+		{"", `\!important`, "!important", false, true},
+		{"", "tmp   ", "tmp", false, true},
+	}
+	for _, tc := range testCases {
+		p, ok := parseIgnorePattern(tc.base, tc.pattern)
+		if !ok {
+			t.Errorf("parseIgnorePattern(%q, %q) reported no pattern", tc.base, tc.pattern)
+			continue
+		}
+		if got := p.match(tc.path, tc.isDir); got != tc.want {
+			t.Errorf("pattern %q in %q: match(%q, %t) = %t; want %t", tc.pattern, tc.base, tc.path, tc.isDir, got, tc.want)
+		}
+	}
+}
+
+func TestParseIgnorePatternSkipped(t *testing.T) {
+	for _, line := range []string{"", "   ", "# comment", "/", "\r"} {
+		if p, ok := parseIgnorePattern("", line); ok {
+			t.Errorf("parseIgnorePattern(%q) = %+v, true; want false", line, p)
+		}
+	}
+}
+
+func TestIgnoreListNegation(t *testing.T) {
+	var l ignoreList
+	for _, line := range []string{"*.log", "!keep.log", "keep.log.d/"} {
+		p, _ := parseIgnorePattern("", line)
+		l = append(l, p)
+	}
+	// A deeper .gitignore re-ignores what the root one re-included.
+	p, _ := parseIgnorePattern("deep", "keep.log")
+	l = append(l, p)
+	testCases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"a.log", false, true},
+		{"keep.log", false, false},
+		{"x/keep.log", false, false},
+		{"keep.log.d", true, true},
+		{"deep/keep.log", false, true},
+		{"a.txt", false, false},
+	}
+	for _, tc := range testCases {
+		if got := l.ignored(tc.path, tc.isDir); got != tc.want {
+			t.Errorf("ignored(%q, %t) = %t; want %t", tc.path, tc.isDir, got, tc.want)
+		}
+	}
+}
+
+func TestParseExcludePattern(t *testing.T) {
+	for _, s := range []string{"!a", "", "# comment"} {
+		if _, err := parseExcludePattern(s); err == nil {
+			t.Errorf("parseExcludePattern(%q) = _, nil; want error", s)
+		}
+	}
+	if _, err := parseExcludePattern("*.o"); err != nil {
+		t.Errorf("parseExcludePattern(%q) = _, %v; want no error", "*.o", err)
+	}
+}
+
+func TestDirFilterWalker(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		".gitignore":          "*.o\n/bin/\n!keep.o\n",
+		".git/HEAD":           "ref: refs/heads/master\n",
+		"a.go":                "",
+		"a.o":                 "",
+		"keep.o":              "",
+		"bin/tool":            "",
+		"src/bin/tool.go":     "",
+		"src/.gitignore":      "gen/\n*.tmp\n",
+		"src/x.tmp":           "",
+		"src/gen/z.go":        "",
+		"src/sub/keep.o":      "",
+		"src/sub/y.tmp":       "",
+		"other/x.tmp":         "",
+		"other/vendor/v.go":   "",
+		"other/vendor/v_test": "",
+	}
+	for name, data := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exclude, err := parseExcludePattern("other/vendor/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		desc   string
+		filter *dirFilter
+		want   []string
+	}{
+		{
+			desc:   "nil",
+			filter: nil,
+			want: []string{".git", ".git/HEAD", ".gitignore", "a.go", "a.o", "bin", "bin/tool", "keep.o",
+				"other", "other/vendor", "other/vendor/v.go", "other/vendor/v_test", "other/x.tmp",
+				"src", "src/.gitignore", "src/bin", "src/bin/tool.go", "src/gen", "src/gen/z.go",
+				"src/sub", "src/sub/keep.o", "src/sub/y.tmp", "src/x.tmp"},
+		},
+		{
+			desc:   "gitignore",
+			filter: &dirFilter{useGitignore: true},
+			want: []string{".gitignore", "a.go", "keep.o",
+				"other", "other/vendor", "other/vendor/v.go", "other/vendor/v_test", "other/x.tmp",
+				"src", "src/.gitignore", "src/bin", "src/bin/tool.go", "src/sub", "src/sub/keep.o"},
+		},
+		{
+			desc:   "exclude",
+			filter: &dirFilter{excludes: ignoreList{exclude}},
+			want: []string{".git", ".git/HEAD", ".gitignore", "a.go", "a.o", "bin", "bin/tool", "keep.o",
+				"other", "other/x.tmp",
+				"src", "src/.gitignore", "src/bin", "src/bin/tool.go", "src/gen", "src/gen/z.go",
+				"src/sub", "src/sub/keep.o", "src/sub/y.tmp", "src/x.tmp"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			skip := tc.filter.walker()
+			var got []string
+			err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				rel, err := filepath.Rel(root, path)
+				if err != nil {
+					return err
+				}
+				skipped, err := skip(path, rel, d.IsDir())
+				if err != nil {
+					return err
+				}
+				if skipped {
+					if d.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				if rel != "." {
+					got = append(got, filepath.ToSlash(rel))
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("walk kept %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
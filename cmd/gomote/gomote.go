@@ -23,6 +23,7 @@ To list the subcommands, run "gomote" without arguments:
 
 	Commands:
 
+	  cp         copy a directory from one buildlet to others
 	  create     create a buildlet; with no args, list types of buildlets
 	  destroy    destroy a buildlet
 	  gettar     extract a tar.gz from a buildlet
@@ -31,6 +32,7 @@ To list the subcommands, run "gomote" without arguments:
 	  ping       test whether a buildlet is alive and reachable
 	  push       sync your GOROOT directory to the buildlet
 	  put        put files on a buildlet
+	  put-status list what was recently put on a buildlet
 	  put14      put Go 1.4 in place
 	  puttar     extract a tar.gz to a buildlet
 	  rm         delete files or directories
@@ -204,6 +206,7 @@ func registerCommand(name, des string, run func([]string) error) {
 }
 
 func registerCommands() {
+	registerCommand("cp", "copy a directory from one buildlet to others", cp)
 	registerCommand("create", "create a buildlet; with no args, list types of buildlets", create)
 	registerCommand("destroy", "destroy a buildlet", destroy)
 	registerCommand("gettar", "extract a tar.gz from a buildlet", getTar)
@@ -213,6 +216,7 @@ func registerCommands() {
 	registerCommand("ping", "test whether a buildlet is alive and reachable ", ping)
 	registerCommand("push", "sync your GOROOT directory to the buildlet", push)
 	registerCommand("put", "put files on a buildlet", put)
+	registerCommand("put-status", "list what was recently put on a buildlet", putStatus)
 	registerCommand("putbootstrap", "put bootstrap toolchain in place", putBootstrap)
 	registerCommand("puttar", "extract a tar.gz to a buildlet", putTar)
 	registerCommand("rdp", "Unimplimented: RDP (Remote Desktop Protocol) to a Windows buildlet", rdp)
@@ -0,0 +1,80 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/build/internal/gomote/protos"
+)
+
+const (
+	// healthPollInterval is how often -wait-healthy checks the health
+	// of a saturated instance.
+	healthPollInterval = 15 * time.Second
+
+	// defaultMaxLoadPerCPU and defaultMinFreeMemory are the default
+	// thresholds beyond which an instance looks saturated.
+	defaultMaxLoadPerCPU = 1.0
+	defaultMinFreeMemory = 512 << 20
+)
+
+// healthGated returns write, preceded by a check of each instance's
+// health if -check-health or -wait-healthy is set. A saturated instance
+// is warned about, or with -wait-healthy, waited for. Instances whose
+// health can't be checked, such as those not running Linux, are written
+// to regardless.
+func (o *putOptions) healthGated(write func(ctx context.Context, inst string) error) func(ctx context.Context, inst string) error {
+	if !o.checkHealth && !o.waitHealthy {
+		return write
+	}
+	return func(ctx context.Context, inst string) error {
+		for {
+			busy, err := o.instanceBusy(ctx, inst)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "# %s: unable to check health: %v\n", inst, err)
+				break
+			}
+			if busy == "" {
+				break
+			}
+			if !o.waitHealthy {
+				fmt.Fprintf(os.Stderr, "# %s: warning: instance looks saturated: %s\n", inst, busy)
+				break
+			}
+			fmt.Fprintf(os.Stderr, "# %s: instance looks saturated (%s); checking again in %s\n", inst, busy, healthPollInterval)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(healthPollInterval):
+			}
+		}
+		return write(ctx, inst)
+	}
+}
+
+// instanceBusy describes why inst looks saturated, or returns "" if it
+// doesn't.
+func (o *putOptions) instanceBusy(ctx context.Context, inst string) (string, error) {
+	client := gomoteServerClient(ctx)
+	h, err := client.InstanceHealth(ctx, &protos.InstanceHealthRequest{GomoteId: inst})
+	if err != nil {
+		return "", err
+	}
+	var why []string
+	if n := h.GetNumCpu(); n > 0 {
+		if load := h.GetLoadAverage() / float64(n); load > o.maxLoadPerCPU {
+			why = append(why, fmt.Sprintf("load is %.2f per CPU, above %.2f", load, o.maxLoadPerCPU))
+		}
+	}
+	if free := h.GetMemAvailableBytes(); free < o.minFreeMemory {
+		why = append(why, fmt.Sprintf("%s of memory is available, below %s", formatBytes(free), formatBytes(o.minFreeMemory)))
+	}
+	return strings.Join(why, "; "), nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// hooked returns a function which writes src to dst on an instance using
+// write and then, if it succeeded, runs the -after-upload-hook command.
+// A failing hook is reported, and only fails the write with -hook-strict.
+// If there's no hook, it returns write unchanged.
+func (o *putOptions) hooked(src, dst string, write func(ctx context.Context, inst string) error) func(ctx context.Context, inst string) error {
+	if o.afterUploadHook == "" {
+		return write
+	}
+	return func(ctx context.Context, inst string) error {
+		// Writes to an instance are sequential, so the digest recorded
+		// for it is the one for this write.
+		o.hookMu.Lock()
+		delete(o.hookSums, inst)
+		o.hookMu.Unlock()
+		if err := write(ctx, inst); err != nil {
+			return err
+		}
+		o.hookMu.Lock()
+		sum := o.hookSums[inst]
+		o.hookMu.Unlock()
+		if err := o.runHook(ctx, inst, src, dst, sum); err != nil {
+			if o.hookStrict {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "# %s: warning: %v\n", inst, err)
+		}
+		return nil
+	}
+}
+
+// recordHookSum records the formatted digest of an upload to inst for the
+// -after-upload-hook, if there is one.
+func (o *putOptions) recordHookSum(inst, sum string) {
+	if o.afterUploadHook == "" {
+		return
+	}
+	o.hookMu.Lock()
+	defer o.hookMu.Unlock()
+	if o.hookSums == nil {
+		o.hookSums = make(map[string]string)
+	}
+	o.hookSums[inst] = sum
+}
+
+// runHook runs the -after-upload-hook command for a successful write.
+//
+// Like -transform, the command is split into fields on white space and run
+// without a shell. It's run with $GOMOTE_INSTANCE, $GOMOTE_SOURCE, and
+// $GOMOTE_DESTINATION set, and $GOMOTE_DIGEST set to the digest of what was
+// uploaded from this machine (see -checksum-algo), or empty if the instance
+// fetched the source itself. Its output goes to stderr.
+func (o *putOptions) runHook(ctx context.Context, inst, src, dst, sum string) error {
+	args := strings.Fields(o.afterUploadHook)
+	if len(args) == 0 {
+		return fmt.Errorf("empty -after-upload-hook command")
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(),
+		"GOMOTE_INSTANCE="+inst,
+		"GOMOTE_SOURCE="+src,
+		"GOMOTE_DESTINATION="+dst,
+		"GOMOTE_DIGEST="+sum,
+	)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("-after-upload-hook for %s: %w", dst, err)
+	}
+	return nil
+}
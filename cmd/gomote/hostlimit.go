@@ -0,0 +1,47 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+// hostLimiter limits the number of uploads in flight to each host.
+type hostLimiter struct {
+	n int // maximum uploads in flight to a host, or 0 for no limit
+
+	mu   sync.Mutex
+	sems map[string]chan struct{} // by host
+}
+
+// acquire waits until an upload to the host of rawURL may start, and
+// returns a function to call once it's done.
+func (l *hostLimiter) acquire(ctx context.Context, rawURL string) (release func(), err error) {
+	if l.n <= 0 {
+		return func() {}, nil
+	}
+	host := rawURL
+	if u, err := url.Parse(rawURL); err == nil {
+		host = u.Host
+	}
+	l.mu.Lock()
+	sem, ok := l.sems[host]
+	if !ok {
+		if l.sems == nil {
+			l.sems = make(map[string]chan struct{})
+		}
+		sem = make(chan struct{}, l.n)
+		l.sems[host] = sem
+	}
+	l.mu.Unlock()
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
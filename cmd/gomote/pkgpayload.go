@@ -0,0 +1,443 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Package formats understood by packagePayloadTarGz.
+const (
+	pkgDeb = "deb"
+	pkgRPM = "rpm"
+)
+
+var (
+	debMagic = []byte("!<arch>\ndebian-binary")
+	rpmMagic = []byte{0xed, 0xab, 0xee, 0xdb}
+)
+
+// packageFormat returns the package format of a file named name which
+// starts with head, or the empty string if it is not a .deb or .rpm package.
+func packageFormat(name string, head []byte) string {
+	switch {
+	case bytes.HasPrefix(head, debMagic), strings.HasSuffix(name, ".deb") && bytes.HasPrefix(head, []byte("!<arch>\n")):
+		return pkgDeb
+	case bytes.HasPrefix(head, rpmMagic):
+		return pkgRPM
+	}
+	return ""
+}
+
+// localPackageFormat returns the package format of the local file at path,
+// or the empty string if it is not a .deb or .rpm package.
+func localPackageFormat(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("opening %q: %w", path, err)
+	}
+	defer f.Close()
+	head := make([]byte, len(debMagic))
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", fmt.Errorf("reading %q: %w", path, err)
+	}
+	return packageFormat(path, head[:n]), nil
+}
+
+// packagePayloadTarGz returns a .tar.gz of the files installed by the
+// package in r, which is in the given format. Control files, scripts, and
+// other package metadata are not included. Nor are symlinks, which are
+// common in packages but skipped by instances when they extract a tarball,
+// so each one is reported to warn instead. Hard links in RPM payloads
+// become copies of the file they link to, and those in Debian payloads are
+// reported like symlinks. Entry names are relative, so "/usr/bin/foo" in
+// the package becomes "usr/bin/foo". Headers are written in tarFormat
+// (see setTarFormat).
+func packagePayloadTarGz(r io.Reader, format string, tarFormat tar.Format, warn io.Writer) (*bytes.Buffer, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := newTarWriter(zw, tarFormat)
+	var err error
+	switch format {
+	case pkgDeb:
+		err = copyDebPayload(tw, r, warn)
+	case pkgRPM:
+		err = copyRPMPayload(tw, r, warn)
+	default:
+		err = fmt.Errorf("unknown package format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("extracting %s payload: %w", format, err)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// copyDebPayload copies the entries of the data.tar member of the
+// Debian package in r to tw.
+func copyDebPayload(tw *tarWriter, r io.Reader, warn io.Writer) error {
+	br := bufio.NewReader(r)
+	magic := make([]byte, 8)
+	if _, err := io.ReadFull(br, magic); err != nil || string(magic) != "!<arch>\n" {
+		return errors.New("not an ar archive")
+	}
+	for {
+		// See https://en.wikipedia.org/wiki/Ar_(Unix)#File_header.
+		var hdr [60]byte
+		if _, err := io.ReadFull(br, hdr[:]); err == io.EOF {
+			return errors.New("no data.tar member found")
+		} else if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(strings.TrimSpace(string(hdr[:16])), "/")
+		size, err := strconv.ParseInt(strings.TrimSpace(string(hdr[48:58])), 10, 64)
+		if err != nil {
+			return fmt.Errorf("bad size for ar member %q", name)
+		}
+		member := io.LimitReader(br, size)
+		if strings.HasPrefix(name, "data.tar") {
+			dr, cleanup, err := decompress(member)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			err = copyTarEntries(tw, tar.NewReader(dr), warn)
+			if cerr := cleanup(); err == nil && cerr != nil {
+				err = fmt.Errorf("%s: %w", name, cerr)
+			}
+			return err
+		}
+		// Members are aligned to an even offset.
+		if _, err := io.CopyN(io.Discard, br, size+size%2); err != nil {
+			return err
+		}
+	}
+}
+
+// copyTarEntries copies the directories and regular files read from tr to
+// tw, warning about each symlink and hard link left out.
+func copyTarEntries(tw *tarWriter, tr *tar.Reader, warn io.Writer) error {
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := payloadName(h.Name)
+		switch {
+		case name == "":
+			continue
+		case h.Typeflag == tar.TypeSymlink:
+			warnSkippedLink(warn, "symlink", name, h.Linkname)
+			continue
+		case h.Typeflag == tar.TypeLink:
+			warnSkippedLink(warn, "hard link", name, payloadName(h.Linkname))
+			continue
+		case h.Typeflag != tar.TypeDir && h.Typeflag != tar.TypeReg:
+			continue
+		}
+		h.Name = name
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		if h.Typeflag == tar.TypeReg {
+			if _, err := io.CopyN(tw, tr, h.Size); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// copyRPMPayload copies the files in the cpio payload of the RPM
+// package in r to tw.
+//
+// See https://rpm-software-management.github.io/rpm/manual/format.html.
+func copyRPMPayload(tw *tarWriter, r io.Reader, warn io.Writer) error {
+	br := bufio.NewReader(r)
+	// Skip the lead.
+	if _, err := io.CopyN(io.Discard, br, 96); err != nil {
+		return err
+	}
+	// Skip the signature header, which is padded to a multiple of 8 bytes,
+	// and then the main header.
+	for _, pad := range []bool{true, false} {
+		var hdr [16]byte
+		if _, err := io.ReadFull(br, hdr[:]); err != nil {
+			return err
+		}
+		if !bytes.Equal(hdr[:3], []byte{0x8e, 0xad, 0xe8}) {
+			return errors.New("bad header magic")
+		}
+		nindex := int64(binary.BigEndian.Uint32(hdr[8:12]))
+		hsize := int64(binary.BigEndian.Uint32(hdr[12:16]))
+		n := nindex*16 + hsize
+		if pad {
+			n += (8 - n%8) % 8
+		}
+		if _, err := io.CopyN(io.Discard, br, n); err != nil {
+			return err
+		}
+	}
+	cr, cleanup, err := decompress(br)
+	if err != nil {
+		return err
+	}
+	err = copyCpioEntries(tw, bufio.NewReader(cr), warn)
+	if cerr := cleanup(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// copyCpioEntries copies the directories and regular files in the "newc"
+// format cpio archive read from r to tw, warning about each symlink left out.
+//
+// Each name of a file with several hard links is written as a copy of the
+// file, since instances don't extract hard links either. The contents are
+// stored with just one of the names, usually the last, and the others are
+// empty, so names seen before the contents are held until they turn up.
+func copyCpioEntries(tw *tarWriter, r *bufio.Reader, warn io.Writer) error {
+	type inode struct {
+		devMajor, devMinor, ino int64
+	}
+	type linkedFile struct {
+		seen    int64         // names seen so far
+		data    []byte        // the contents, once seen
+		pending []*tar.Header // names seen before the contents
+	}
+	links := make(map[inode]*linkedFile)
+	var linkOrder []inode // of first appearance
+	writeFile := func(h *tar.Header, data []byte) error {
+		h.Size = int64(len(data))
+		if err := tw.WriteHeader(h); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	var off int64
+	skip := func(n int64) error {
+		off += n
+		_, err := io.CopyN(io.Discard, r, n)
+		return err
+	}
+	align := func() error {
+		return skip((4 - off%4) % 4)
+	}
+	for {
+		var hdr [110]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return err
+		}
+		off += int64(len(hdr))
+		if magic := string(hdr[:6]); magic != "070701" && magic != "070702" {
+			return fmt.Errorf("unsupported cpio format %q", magic)
+		}
+		field := func(i int) (int64, error) {
+			return strconv.ParseInt(string(hdr[6+8*i:6+8*(i+1)]), 16, 64)
+		}
+		var fields [13]int64
+		for i := range fields {
+			v, err := field(i)
+			if err != nil {
+				return fmt.Errorf("bad cpio header: %w", err)
+			}
+			fields[i] = v
+		}
+		ino, mode, nlink, mtime, size, namesize := fields[0], fields[1], fields[4], fields[5], fields[6], fields[11]
+		devMajor, devMinor := fields[7], fields[8]
+		nameBuf := make([]byte, namesize)
+		if _, err := io.ReadFull(r, nameBuf); err != nil {
+			return err
+		}
+		off += namesize
+		if err := align(); err != nil {
+			return err
+		}
+		rawName := strings.TrimRight(string(nameBuf), "\x00")
+		if rawName == "TRAILER!!!" {
+			// Names whose contents never turned up are of an empty file.
+			for _, key := range linkOrder {
+				f, ok := links[key]
+				if !ok {
+					continue
+				}
+				for _, h := range f.pending {
+					if err := writeFile(h, nil); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		}
+		h := &tar.Header{
+			Name:    payloadName(rawName),
+			Mode:    mode & 07777,
+			ModTime: time.Unix(mtime, 0),
+		}
+		const (
+			cpioTypeMask = 0170000
+			cpioDir      = 0040000
+			cpioReg      = 0100000
+			cpioSymlink  = 0120000
+		)
+		data := io.LimitReader(r, size)
+		switch mode & cpioTypeMask {
+		case cpioDir:
+			h.Typeflag = tar.TypeDir
+		case cpioReg:
+			h.Typeflag = tar.TypeReg
+			h.Size = size
+		case cpioSymlink:
+			target, err := io.ReadAll(data)
+			if err != nil {
+				return err
+			}
+			if h.Name != "" {
+				warnSkippedLink(warn, "symlink", h.Name, string(target))
+			}
+			h.Name = ""
+		default:
+			h.Name = ""
+		}
+		if h.Typeflag == tar.TypeReg && nlink > 1 {
+			key := inode{devMajor, devMinor, ino}
+			f := links[key]
+			if f == nil {
+				f = &linkedFile{}
+				links[key] = f
+				linkOrder = append(linkOrder, key)
+			}
+			f.seen++
+			if size > 0 {
+				var err error
+				if f.data, err = io.ReadAll(data); err != nil {
+					return err
+				}
+				for _, ph := range f.pending {
+					if err := writeFile(ph, f.data); err != nil {
+						return err
+					}
+				}
+				f.pending = nil
+			}
+			switch {
+			case h.Name == "":
+			case f.data != nil:
+				if err := writeFile(h, f.data); err != nil {
+					return err
+				}
+			default:
+				f.pending = append(f.pending, h)
+			}
+			if f.seen == nlink && f.data != nil {
+				delete(links, key)
+			}
+		} else if h.Name != "" {
+			if err := tw.WriteHeader(h); err != nil {
+				return err
+			}
+			if h.Typeflag == tar.TypeReg {
+				if _, err := io.Copy(tw, data); err != nil {
+					return err
+				}
+			}
+		}
+		// Discard whatever was not consumed above.
+		if _, err := io.Copy(io.Discard, data); err != nil {
+			return err
+		}
+		off += size
+		if err := align(); err != nil {
+			return err
+		}
+	}
+}
+
+// warnSkippedLink reports to w that the link of the given kind in a package
+// payload, from name to target, isn't put on instances.
+func warnSkippedLink(w io.Writer, kind, name, target string) {
+	fmt.Fprintf(w, "# skipping %s %s -> %s in package payload, since instances don't extract %ss\n", kind, name, target, kind)
+}
+
+// payloadName returns the relative name to use for a file installed by a
+// package at name, or the empty string if it refers to the root directory.
+func payloadName(name string) string {
+	return strings.TrimPrefix(path.Clean("/"+name), "/")
+}
+
+// decompress returns a reader of the decompressed contents of r, detecting
+// the compression from its leading bytes. The returned cleanup function must
+// be called once reading is complete. It reports whether decompression
+// failed, which a reader stopping at the end of an archive may not see.
+//
+// Formats without a decoder in the standard library (xz, lzma, and zstd) are
+// decompressed using the corresponding tool, which must be installed locally.
+func decompress(r io.Reader) (io.Reader, func() error, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(6)
+	noop := func() error { return nil }
+	var tool []string
+	switch {
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}):
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, nil, err
+		}
+		return zr, noop, nil
+	case bytes.HasPrefix(head, []byte("BZh")):
+		return bzip2.NewReader(br), noop, nil
+	case bytes.HasPrefix(head, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		tool = []string{"xz", "-dc"}
+	case bytes.HasPrefix(head, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		tool = []string{"zstd", "-dc"}
+	case bytes.HasPrefix(head, []byte{0x5d, 0x00, 0x00}):
+		tool = []string{"xz", "--format=lzma", "-dc"}
+	default:
+		// Assume it is not compressed.
+		return br, noop, nil
+	}
+	cmd := exec.Command(tool[0], tool[1:]...)
+	cmd.Stdin = br
+	cmd.Stderr = os.Stderr
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, fmt.Errorf("decompressing with %s: %w", tool[0], err)
+	}
+	cleanup := func() error {
+		// Read whatever is left, such as padding after the end of an
+		// archive, so that the tool doesn't fail writing it.
+		io.Copy(io.Discard, out)
+		if err := cmd.Wait(); err != nil {
+			return fmt.Errorf("decompressing with %s: %w", tool[0], err)
+		}
+		return nil
+	}
+	return out, cleanup, nil
+}
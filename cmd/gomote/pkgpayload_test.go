@@ -0,0 +1,428 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// arMember is a member of an ar archive built by arArchive.
+type arMember struct {
+	name string
+	data []byte
+}
+
+// arArchive returns an ar archive, as used by Debian packages, of members.
+func arArchive(members ...arMember) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("!<arch>\n")
+	for _, m := range members {
+		fmt.Fprintf(&buf, "%-16s%-12d%-6d%-6d%-8s%-10d`\n", m.name+"/", 0, 0, 0, "100644", len(m.data))
+		buf.Write(m.data)
+		if len(m.data)%2 == 1 {
+			buf.WriteByte('\n')
+		}
+	}
+	return buf.Bytes()
+}
+
+// testEntry is an entry of an archive built for a test.
+type testEntry struct {
+	name   string
+	mode   int64
+	typ    byte // a tar.Type constant
+	body   string
+	target string // of a link
+
+	// ino and nlink are the inode and number of links of a regular file
+	// in a cpio archive, where hard links share an inode. If nlink is
+	// zero, the file has one link.
+	ino, nlink int64
+}
+
+// tarArchive returns a tar archive of entries.
+func tarArchive(t *testing.T, entries ...testEntry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		h := &tar.Header{Name: e.name, Mode: e.mode, Typeflag: e.typ, Size: int64(len(e.body)), Linkname: e.target}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(tw, e.body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// cpioArchive returns a "newc" format cpio archive of entries, with
+// the trailer unless truncated.
+func cpioArchive(entries ...testEntry) []byte {
+	var buf bytes.Buffer
+	pad := func() {
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	add := func(name string, mode, ino, nlink int64, data string) {
+		if nlink == 0 {
+			nlink = 1
+		}
+		fmt.Fprintf(&buf, "070701%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x%08x",
+			ino, mode, 0, 0, nlink, 0, len(data), 0, 0, 0, 0, len(name)+1, 0)
+		buf.WriteString(name + "\x00")
+		pad()
+		buf.WriteString(data)
+		pad()
+	}
+	for _, e := range entries {
+		switch e.typ {
+		case tar.TypeDir:
+			add(e.name, 0040000|e.mode, e.ino, 0, "")
+		case tar.TypeReg:
+			add(e.name, 0100000|e.mode, e.ino, e.nlink, e.body)
+		case tar.TypeSymlink:
+			add(e.name, 0120000|e.mode, e.ino, 0, e.target)
+		case tar.TypeChar:
+			add(e.name, 0020000|e.mode, e.ino, 0, "")
+		}
+	}
+	add("TRAILER!!!", 0, 0, 0, "")
+	return buf.Bytes()
+}
+
+// rpmPackage returns an RPM package with the given payload, after a lead,
+// a signature header whose size needs padding, and a main header.
+func rpmPackage(payload []byte) []byte {
+	var buf bytes.Buffer
+	lead := make([]byte, 96)
+	copy(lead, rpmMagic)
+	buf.Write(lead)
+	header := func(nindex, hsize int) {
+		buf.Write([]byte{0x8e, 0xad, 0xe8, 0x01, 0, 0, 0, 0})
+		buf.Write([]byte{0, 0, 0, byte(nindex), 0, 0, 0, byte(hsize)})
+		buf.Write(make([]byte, nindex*16+hsize))
+	}
+	header(1, 5)
+	buf.Write(make([]byte, 3)) // pads the signature to a multiple of 8
+	header(2, 7)
+	buf.Write(payload)
+	return buf.Bytes()
+}
+
+func gzipped(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// tarGzEntries lists the entries of a .tar.gz as "name mode" for
+// directories and "name mode body" for regular files.
+func tarGzEntries(t *testing.T, tgz []byte) []string {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(tgz))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(zr)
+	var entries []string
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return entries
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h.Typeflag == tar.TypeReg {
+			entries = append(entries, fmt.Sprintf("%s %o %s", h.Name, h.Mode, body))
+		} else {
+			entries = append(entries, fmt.Sprintf("%s %o", h.Name, h.Mode))
+		}
+	}
+}
+
+func TestPackagePayloadTarGz(t *testing.T) {
+	payload := []testEntry{
+		{name: "./usr/", mode: 0755, typ: tar.TypeDir},
+		{name: "./usr/bin/foo", mode: 0755, typ: tar.TypeReg, body: "#!/bin/sh\n"},
+		{name: "./usr/bin/bar", mode: 0777, typ: tar.TypeSymlink, target: "foo"},
+		{name: "/etc/foo.conf", mode: 0644, typ: tar.TypeReg, body: "x=1"},
+		{name: "./", mode: 0755, typ: tar.TypeDir},
+	}
+	want := []string{
+		"usr 755",
+		"usr/bin/foo 755 #!/bin/sh\n",
+		"etc/foo.conf 644 x=1",
+	}
+	wantWarnings := []string{
+		"# skipping symlink usr/bin/bar -> foo in package payload, since instances don't extract symlinks",
+	}
+	dataTar := tarArchive(t, payload...)
+	cpio := cpioArchive(append(payload, testEntry{name: "dev/null", mode: 0666, typ: tar.TypeChar})...)
+	deb := arArchive(
+		arMember{"debian-binary", []byte("2.0\n")},
+		arMember{"control.tar.gz", []byte("odd")}, // needs padding
+		arMember{"data.tar.gz", gzipped(t, dataTar)},
+	)
+	testCases := []struct {
+		desc    string
+		format  string
+		data    []byte
+		want    []string
+		wantErr bool
+	}{
+		{
+			desc:   "deb",
+			format: pkgDeb,
+			data:   deb,
+			want:   want,
+		},
+		{
+			desc:   "deb with uncompressed data",
+			format: pkgDeb,
+			data:   arArchive(arMember{"debian-binary", []byte("2.0\n")}, arMember{"data.tar", dataTar}),
+			want:   want,
+		},
+		{
+			desc:    "deb without data",
+			format:  pkgDeb,
+			data:    arArchive(arMember{"debian-binary", []byte("2.0\n")}),
+			wantErr: true,
+		},
+		{
+			desc:    "not an ar archive",
+			format:  pkgDeb,
+			data:    []byte("!<arch>"),
+			wantErr: true,
+		},
+		{
+			desc:    "truncated ar header",
+			format:  pkgDeb,
+			data:    deb[:8+30],
+			wantErr: true,
+		},
+		{
+			desc:    "truncated deb data",
+			format:  pkgDeb,
+			data:    deb[:len(deb)-20],
+			wantErr: true,
+		},
+		{
+			desc:    "bad ar member size",
+			format:  pkgDeb,
+			data:    append([]byte("!<arch>\ndebian-binary/  0           0     0     100644  four      `\n"), "2.0\n"...),
+			wantErr: true,
+		},
+		{
+			desc:   "rpm",
+			format: pkgRPM,
+			data:   rpmPackage(gzipped(t, cpio)),
+			want:   want,
+		},
+		{
+			desc:   "rpm with uncompressed payload",
+			format: pkgRPM,
+			data:   rpmPackage(cpio),
+			want:   want,
+		},
+		{
+			desc:    "truncated rpm lead",
+			format:  pkgRPM,
+			data:    rpmMagic,
+			wantErr: true,
+		},
+		{
+			desc:    "bad rpm header magic",
+			format:  pkgRPM,
+			data:    append(make([]byte, 96), bytes.Repeat([]byte{0xff}, 16)...),
+			wantErr: true,
+		},
+		{
+			desc:    "truncated cpio",
+			format:  pkgRPM,
+			data:    rpmPackage(cpio[:len(cpio)-130]),
+			wantErr: true,
+		},
+		{
+			desc:    "unsupported cpio format",
+			format:  pkgRPM,
+			data:    rpmPackage(append([]byte("070707"), cpio[6:]...)),
+			wantErr: true,
+		},
+		{
+			desc:    "corrupt cpio header",
+			format:  pkgRPM,
+			data:    rpmPackage(append([]byte("070701zzzzzzzz"), cpio[14:]...)),
+			wantErr: true,
+		},
+		{
+			desc:    "truncated compressed payload",
+			format:  pkgRPM,
+			data:    rpmPackage(gzipped(t, cpio)[:40]),
+			wantErr: true,
+		},
+		{
+			desc:    "unknown format",
+			format:  "apk",
+			data:    deb,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var warnings bytes.Buffer
+			tgz, err := packagePayloadTarGz(bytes.NewReader(tc.data), tc.format, tar.FormatUnknown, &warnings)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("packagePayloadTarGz(%s) = %v, nil; want error", tc.format, tarGzEntries(t, tgz.Bytes()))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("packagePayloadTarGz(%s) = %s; want no error", tc.format, err)
+			}
+			if got := tarGzEntries(t, tgz.Bytes()); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("packagePayloadTarGz(%s) entries = %q; want %q", tc.format, got, tc.want)
+			}
+			if got := warningLines(&warnings); !reflect.DeepEqual(got, wantWarnings) {
+				t.Errorf("packagePayloadTarGz(%s) warnings = %q; want %q", tc.format, got, wantWarnings)
+			}
+		})
+	}
+}
+
+// warningLines returns the lines written to w.
+func warningLines(w *bytes.Buffer) []string {
+	if w.Len() == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n")
+}
+
+func TestPackagePayloadHardLinks(t *testing.T) {
+	testCases := []struct {
+		desc         string
+		format       string
+		data         []byte
+		want         []string
+		wantWarnings []string
+	}{
+		{
+			desc:   "rpm",
+			format: pkgRPM,
+			// As written by rpm, the contents are stored with the last name
+			// of a file, but the first name may hold them instead.
+			data: rpmPackage(cpioArchive(
+				testEntry{name: "./usr/bin/a", mode: 0755, typ: tar.TypeReg, ino: 7, nlink: 3},
+				testEntry{name: "./usr/bin/other", mode: 0755, typ: tar.TypeReg, ino: 8, body: "other"},
+				testEntry{name: "./usr/bin/b", mode: 0755, typ: tar.TypeReg, ino: 7, nlink: 3},
+				testEntry{name: "./usr/bin/c", mode: 0755, typ: tar.TypeReg, ino: 7, nlink: 3, body: "linked"},
+				testEntry{name: "./usr/lib/x", mode: 0644, typ: tar.TypeReg, ino: 9, nlink: 2, body: "first"},
+				testEntry{name: "./usr/lib/y", mode: 0644, typ: tar.TypeReg, ino: 9, nlink: 2},
+				testEntry{name: "./usr/share/empty1", mode: 0644, typ: tar.TypeReg, ino: 10, nlink: 2},
+				testEntry{name: "./usr/share/empty2", mode: 0644, typ: tar.TypeReg, ino: 10, nlink: 2},
+			)),
+			want: []string{
+				"usr/bin/other 755 other",
+				"usr/bin/a 755 linked",
+				"usr/bin/b 755 linked",
+				"usr/bin/c 755 linked",
+				"usr/lib/x 644 first",
+				"usr/lib/y 644 first",
+				"usr/share/empty1 644 ",
+				"usr/share/empty2 644 ",
+			},
+		},
+		{
+			desc:   "deb",
+			format: pkgDeb,
+			data: arArchive(arMember{"data.tar", tarArchive(t,
+				testEntry{name: "./usr/bin/a", mode: 0755, typ: tar.TypeReg, body: "linked"},
+				testEntry{name: "./usr/bin/b", mode: 0755, typ: tar.TypeLink, target: "./usr/bin/a"},
+			)}),
+			want: []string{"usr/bin/a 755 linked"},
+			wantWarnings: []string{
+				"# skipping hard link usr/bin/b -> usr/bin/a in package payload, since instances don't extract hard links",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			var warnings bytes.Buffer
+			tgz, err := packagePayloadTarGz(bytes.NewReader(tc.data), tc.format, tar.FormatUnknown, &warnings)
+			if err != nil {
+				t.Fatalf("packagePayloadTarGz(%s) = %s; want no error", tc.format, err)
+			}
+			if got := tarGzEntries(t, tgz.Bytes()); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("packagePayloadTarGz(%s) entries = %q; want %q", tc.format, got, tc.want)
+			}
+			if got := warningLines(&warnings); !reflect.DeepEqual(got, tc.wantWarnings) {
+				t.Errorf("packagePayloadTarGz(%s) warnings = %q; want %q", tc.format, got, tc.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestPackageFormat(t *testing.T) {
+	testCases := []struct {
+		name string
+		head []byte
+		want string
+	}{
+		{"foo.pkg", []byte("!<arch>\ndebian-binary   "), pkgDeb},
+		{"foo.deb", []byte("!<arch>\ncontrol"), pkgDeb},
+		{"foo.a", []byte("!<arch>\nfoo.o"), ""},
+		{"foo", append(append([]byte{}, rpmMagic...), 3, 0), pkgRPM},
+		{"foo.rpm", []byte("PK\x03\x04"), ""},
+		{"", nil, ""},
+	}
+	for _, tc := range testCases {
+		if got := packageFormat(tc.name, tc.head); got != tc.want {
+			t.Errorf("packageFormat(%q, %q) = %q; want %q", tc.name, tc.head, got, tc.want)
+		}
+	}
+}
+
+func TestPayloadName(t *testing.T) {
+	testCases := []struct {
+		name, want string
+	}{
+		{"./usr/bin/foo", "usr/bin/foo"},
+		{"/usr/bin/foo", "usr/bin/foo"},
+		{"usr/bin/", "usr/bin"},
+		{"../../etc/passwd", "etc/passwd"},
+		{"./", ""},
+		{"/", ""},
+	}
+	for _, tc := range testCases {
+		if got := payloadName(tc.name); got != tc.want {
+			t.Errorf("payloadName(%q) = %q; want %q", tc.name, got, tc.want)
+		}
+	}
+}
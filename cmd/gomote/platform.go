@@ -0,0 +1,246 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"golang.org/x/build/dashboard"
+	"golang.org/x/build/internal/gomote/protos"
+)
+
+// binaryInfo describes the platform an executable was built for.
+type binaryInfo struct {
+	name   string // name of the executable within the source
+	format string // "elf", "macho", or "pe"
+	goos   string // if known from the executable alone
+	goarch string
+
+	// otherArches are the other architectures in a universal Mach-O
+	// binary, which runs on any of them.
+	otherArches []string
+}
+
+func (b *binaryInfo) String() string {
+	goos := b.goos
+	if goos == "" {
+		goos = "(" + b.format + ")"
+	}
+	return fmt.Sprintf("%s/%s", goos, strings.Join(append([]string{b.goarch}, b.otherArches...), "+"))
+}
+
+// matches reports whether the executable can run on goos/goarch.
+func (b *binaryInfo) matches(goos, goarch string) bool {
+	hasArch := goarch == b.goarch
+	for _, arch := range b.otherArches {
+		hasArch = hasArch || goarch == arch
+	}
+	if !hasArch {
+		return false
+	}
+	if b.goos != "" {
+		return goos == b.goos || (b.goos == "darwin" && goos == "ios")
+	}
+	switch goos {
+	case "darwin", "ios", "windows", "plan9":
+		return false
+	}
+	// Everything else uses ELF.
+	return b.format == "elf"
+}
+
+// identifyBinary returns the platform of the executable in r,
+// or nil if it's not an executable in a recognized format.
+func identifyBinary(name string, r io.ReaderAt) *binaryInfo {
+	if f, err := elf.NewFile(r); err == nil {
+		b := &binaryInfo{name: name, format: "elf"}
+		switch f.Machine {
+		case elf.EM_X86_64:
+			b.goarch = "amd64"
+		case elf.EM_386:
+			b.goarch = "386"
+		case elf.EM_AARCH64:
+			b.goarch = "arm64"
+		case elf.EM_ARM:
+			b.goarch = "arm"
+		case elf.EM_PPC64:
+			b.goarch = "ppc64"
+			if f.ByteOrder == binary.LittleEndian {
+				b.goarch = "ppc64le"
+			}
+		case elf.EM_S390:
+			b.goarch = "s390x"
+		case elf.EM_RISCV:
+			b.goarch = "riscv64"
+		case elf.EM_MIPS:
+			b.goarch = "mips"
+			if f.Class == elf.ELFCLASS64 {
+				b.goarch = "mips64"
+			}
+			if f.ByteOrder == binary.LittleEndian {
+				b.goarch += "le"
+			}
+		default:
+			return nil
+		}
+		if f.OSABI == elf.ELFOSABI_FREEBSD {
+			b.goos = "freebsd"
+		}
+		return b
+	}
+	if f, err := macho.NewFile(r); err == nil {
+		goarch := machoGOARCH(f.Cpu)
+		if goarch == "" {
+			return nil
+		}
+		return &binaryInfo{name: name, format: "macho", goos: "darwin", goarch: goarch}
+	}
+	if f, err := macho.NewFatFile(r); err == nil {
+		b := &binaryInfo{name: name, format: "macho", goos: "darwin"}
+		for _, arch := range f.Arches {
+			switch goarch := machoGOARCH(arch.Cpu); {
+			case goarch == "":
+			case b.goarch == "":
+				b.goarch = goarch
+			default:
+				b.otherArches = append(b.otherArches, goarch)
+			}
+		}
+		if b.goarch == "" {
+			return nil
+		}
+		return b
+	}
+	if f, err := pe.NewFile(r); err == nil {
+		b := &binaryInfo{name: name, format: "pe", goos: "windows"}
+		switch f.Machine {
+		case pe.IMAGE_FILE_MACHINE_AMD64:
+			b.goarch = "amd64"
+		case pe.IMAGE_FILE_MACHINE_I386:
+			b.goarch = "386"
+		case pe.IMAGE_FILE_MACHINE_ARM64:
+			b.goarch = "arm64"
+		case pe.IMAGE_FILE_MACHINE_ARMNT:
+			b.goarch = "arm"
+		default:
+			return nil
+		}
+		return b
+	}
+	return nil
+}
+
+// machoGOARCH returns the GOARCH of Mach-O executables for cpu, or the
+// empty string if Go doesn't support it on darwin.
+func machoGOARCH(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuAmd64:
+		return "amd64"
+	case macho.CpuArm64:
+		return "arm64"
+	}
+	return ""
+}
+
+// readBinary returns the platform of the executable named name read
+// from r, or nil if it's not an executable in a recognized format.
+func readBinary(name string, r io.Reader) (*binaryInfo, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return identifyBinary(name, bytes.NewReader(data)), nil
+}
+
+// tarGzBinary returns the platform of a representative executable in the
+// .tar.gz read from r. It prefers the go command of a Go distribution,
+// and otherwise uses the first executable found. It returns nil if
+// the tarball contains no recognized executable.
+func tarGzBinary(r io.Reader) (*binaryInfo, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(zr)
+	var first *binaryInfo
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return first, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		isGo := path.Base(path.Dir(h.Name)) == "bin" && (path.Base(h.Name) == "go" || path.Base(h.Name) == "go.exe")
+		if first != nil && !isGo {
+			continue
+		}
+		b, err := readBinary(h.Name, tr)
+		if err != nil {
+			return nil, err
+		}
+		if b == nil {
+			continue
+		}
+		if isGo {
+			return b, nil
+		}
+		first = b
+	}
+}
+
+// checkPlatform checks that the executable described by bin can run on
+// the platform given by the -platform flag, or, if the flag is "auto", on
+// each of the instances insts.
+func (o *putOptions) checkPlatform(ctx context.Context, insts []string, bin *binaryInfo) error {
+	if bin == nil {
+		fmt.Fprintln(os.Stderr, "# no recognized executable in source; skipping -platform check")
+		return nil
+	}
+	if o.platform != "auto" {
+		goos, goarch, ok := strings.Cut(o.platform, "/")
+		if !ok {
+			return fmt.Errorf("invalid -platform %q: want auto or GOOS/GOARCH", o.platform)
+		}
+		if !bin.matches(goos, goarch) {
+			return fmt.Errorf("source contains a %s executable %s, not %s", bin, bin.name, o.platform)
+		}
+		return nil
+	}
+	client := gomoteServerClient(ctx)
+	resp, err := client.ListInstances(ctx, &protos.ListInstancesRequest{})
+	if err != nil {
+		return fmt.Errorf("unable to list instances: %w", err)
+	}
+	builderTypes := make(map[string]string)
+	for _, inst := range resp.GetInstances() {
+		builderTypes[inst.GetGomoteId()] = inst.GetBuilderType()
+	}
+	for _, inst := range insts {
+		conf, ok := dashboard.Builders[builderTypes[inst]]
+		if !ok {
+			return fmt.Errorf("unable to determine the platform of instance %q; use -platform=GOOS/GOARCH", inst)
+		}
+		if !bin.matches(conf.GOOS(), conf.GOARCH()) {
+			return fmt.Errorf("source contains a %s executable %s, but instance %q is %s/%s", bin, bin.name, inst, conf.GOOS(), conf.GOARCH())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"debug/elf"
+	"debug/macho"
+	"debug/pe"
+	"encoding/binary"
+	"testing"
+)
+
+// elfHeader returns a minimal ELF executable header.
+func elfHeader(t *testing.T, class elf.Class, order binary.ByteOrder, osabi elf.OSABI, machine elf.Machine) []byte {
+	t.Helper()
+	ident := [elf.EI_NIDENT]byte{0x7f, 'E', 'L', 'F', byte(class), 0, byte(elf.EV_CURRENT), byte(osabi)}
+	ident[elf.EI_DATA] = byte(elf.ELFDATA2LSB)
+	if order == binary.BigEndian {
+		ident[elf.EI_DATA] = byte(elf.ELFDATA2MSB)
+	}
+	var buf bytes.Buffer
+	var err error
+	if class == elf.ELFCLASS64 {
+		err = binary.Write(&buf, order, elf.Header64{
+			Ident: ident, Type: uint16(elf.ET_EXEC), Machine: uint16(machine),
+			Version: uint32(elf.EV_CURRENT), Ehsize: 64, Shentsize: 64,
+		})
+	} else {
+		err = binary.Write(&buf, order, elf.Header32{
+			Ident: ident, Type: uint16(elf.ET_EXEC), Machine: uint16(machine),
+			Version: uint32(elf.EV_CURRENT), Ehsize: 52, Shentsize: 40,
+		})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// machoHeader returns a minimal 64-bit Mach-O executable header.
+func machoHeader(t *testing.T, cpu macho.Cpu) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := binary.Write(&buf, binary.LittleEndian, macho.FileHeader{Magic: macho.Magic64, Cpu: cpu, Type: macho.TypeExec}); err != nil {
+		t.Fatal(err)
+	}
+	buf.Write(make([]byte, 4)) // reserved
+	return buf.Bytes()
+}
+
+// fatMachO returns a universal Mach-O binary of minimal executables
+// for cpus.
+func fatMachO(t *testing.T, cpus ...macho.Cpu) []byte {
+	t.Helper()
+	const align = 64
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, []uint32{macho.MagicFat, uint32(len(cpus))})
+	for i, cpu := range cpus {
+		size := len(machoHeader(t, cpu))
+		binary.Write(&buf, binary.BigEndian, macho.FatArchHeader{Cpu: cpu, Offset: uint32(align * (i + 1)), Size: uint32(size), Align: 6})
+	}
+	for _, cpu := range cpus {
+		buf.Write(make([]byte, align-buf.Len()%align))
+		buf.Write(machoHeader(t, cpu))
+	}
+	return buf.Bytes()
+}
+
+// peHeader returns a minimal PE executable header.
+func peHeader(t *testing.T, machine uint16) []byte {
+	t.Helper()
+	dos := make([]byte, 0x80) // debug/pe reads at least 96 bytes
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3c:], uint32(len(dos)))
+	buf := bytes.NewBuffer(dos)
+	buf.WriteString("PE\x00\x00")
+	if err := binary.Write(buf, binary.LittleEndian, pe.FileHeader{Machine: machine}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestIdentifyBinary(t *testing.T) {
+	le, be := binary.LittleEndian, binary.BigEndian
+	testCases := []struct {
+		desc string
+		data []byte
+		want string // or "" for nil
+	}{
+		{"elf amd64", elfHeader(t, elf.ELFCLASS64, le, elf.ELFOSABI_NONE, elf.EM_X86_64), "(elf)/amd64"},
+		{"elf 386", elfHeader(t, elf.ELFCLASS32, le, elf.ELFOSABI_NONE, elf.EM_386), "(elf)/386"},
+		{"elf arm64", elfHeader(t, elf.ELFCLASS64, le, elf.ELFOSABI_NONE, elf.EM_AARCH64), "(elf)/arm64"},
+		{"elf arm", elfHeader(t, elf.ELFCLASS32, le, elf.ELFOSABI_NONE, elf.EM_ARM), "(elf)/arm"},
+		{"elf ppc64", elfHeader(t, elf.ELFCLASS64, be, elf.ELFOSABI_NONE, elf.EM_PPC64), "(elf)/ppc64"},
+		{"elf ppc64le", elfHeader(t, elf.ELFCLASS64, le, elf.ELFOSABI_NONE, elf.EM_PPC64), "(elf)/ppc64le"},
+		{"elf s390x", elfHeader(t, elf.ELFCLASS64, be, elf.ELFOSABI_NONE, elf.EM_S390), "(elf)/s390x"},
+		{"elf riscv64", elfHeader(t, elf.ELFCLASS64, le, elf.ELFOSABI_NONE, elf.EM_RISCV), "(elf)/riscv64"},
+		{"elf mips", elfHeader(t, elf.ELFCLASS32, be, elf.ELFOSABI_NONE, elf.EM_MIPS), "(elf)/mips"},
+		{"elf mipsle", elfHeader(t, elf.ELFCLASS32, le, elf.ELFOSABI_NONE, elf.EM_MIPS), "(elf)/mipsle"},
+		{"elf mips64le", elfHeader(t, elf.ELFCLASS64, le, elf.ELFOSABI_NONE, elf.EM_MIPS), "(elf)/mips64le"},
+		{"elf freebsd", elfHeader(t, elf.ELFCLASS64, le, elf.ELFOSABI_FREEBSD, elf.EM_X86_64), "freebsd/amd64"},
+		{"elf unknown machine", elfHeader(t, elf.ELFCLASS64, le, elf.ELFOSABI_NONE, elf.EM_SPARCV9), ""},
+		{"macho amd64", machoHeader(t, macho.CpuAmd64), "darwin/amd64"},
+		{"macho arm64", machoHeader(t, macho.CpuArm64), "darwin/arm64"},
+		{"macho unknown cpu", machoHeader(t, macho.CpuPpc64), ""},
+		{"fat macho", fatMachO(t, macho.CpuAmd64, macho.CpuArm64), "darwin/amd64+arm64"},
+		{"fat macho with unknown cpu", fatMachO(t, macho.CpuPpc64, macho.CpuArm64), "darwin/arm64"},
+		{"fat macho of unknown cpus", fatMachO(t, macho.CpuPpc64, macho.Cpu386), ""},
+		{"pe amd64", peHeader(t, pe.IMAGE_FILE_MACHINE_AMD64), "windows/amd64"},
+		{"pe 386", peHeader(t, pe.IMAGE_FILE_MACHINE_I386), "windows/386"},
+		{"pe arm64", peHeader(t, pe.IMAGE_FILE_MACHINE_ARM64), "windows/arm64"},
+		{"pe arm", peHeader(t, pe.IMAGE_FILE_MACHINE_ARMNT), "windows/arm"},
+		{"pe unknown machine", peHeader(t, pe.IMAGE_FILE_MACHINE_IA64), ""},
+		{"script", []byte("#!/bin/sh\necho hi\n"), ""},
+		{"empty", nil, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			b := identifyBinary("x", bytes.NewReader(tc.data))
+			got := ""
+			if b != nil {
+				got = b.String()
+			}
+			if got != tc.want {
+				t.Errorf("identifyBinary() = %q; want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestBinaryInfoMatches(t *testing.T) {
+	testCases := []struct {
+		bin          binaryInfo
+		goos, goarch string
+		want         bool
+	}{
+		{binaryInfo{format: "elf", goarch: "amd64"}, "linux", "amd64", true},
+		{binaryInfo{format: "elf", goarch: "amd64"}, "openbsd", "amd64", true},
+		{binaryInfo{format: "elf", goarch: "amd64"}, "linux", "arm64", false},
+		{binaryInfo{format: "elf", goarch: "amd64"}, "darwin", "amd64", false},
+		{binaryInfo{format: "elf", goarch: "amd64"}, "windows", "amd64", false},
+		{binaryInfo{format: "elf", goos: "freebsd", goarch: "amd64"}, "linux", "amd64", false},
+		{binaryInfo{format: "elf", goos: "freebsd", goarch: "amd64"}, "freebsd", "amd64", true},
+		{binaryInfo{format: "macho", goos: "darwin", goarch: "arm64"}, "ios", "arm64", true},
+		{binaryInfo{format: "macho", goos: "darwin", goarch: "amd64", otherArches: []string{"arm64"}}, "darwin", "arm64", true},
+		{binaryInfo{format: "macho", goos: "darwin", goarch: "amd64", otherArches: []string{"arm64"}}, "darwin", "386", false},
+		{binaryInfo{format: "pe", goos: "windows", goarch: "386"}, "windows", "386", true},
+		{binaryInfo{format: "pe", goos: "windows", goarch: "386"}, "windows", "amd64", false},
+	}
+	for _, tc := range testCases {
+		if got := tc.bin.matches(tc.goos, tc.goarch); got != tc.want {
+			t.Errorf("%s matches(%s, %s) = %t; want %t", &tc.bin, tc.goos, tc.goarch, got, tc.want)
+		}
+	}
+}
+
+func TestTarGzBinary(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, f := range []struct {
+		name string
+		data []byte
+	}{
+		{"go/README.md", []byte("# Go\n")},
+		{"go/pkg/tool/linux_amd64/vet", elfHeader(t, elf.ELFCLASS64, binary.LittleEndian, elf.ELFOSABI_NONE, elf.EM_X86_64)},
+		{"go/bin/go", machoHeader(t, macho.CpuArm64)},
+	} {
+		if err := tw.WriteHeader(&tar.Header{Name: f.name, Mode: 0755, Size: int64(len(f.data))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(f.data)
+	}
+	tw.Close()
+	zw.Close()
+	b, err := tarGzBinary(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b == nil || b.name != "go/bin/go" || b.String() != "darwin/arm64" {
+		t.Errorf("tarGzBinary() = %v; want go/bin/go, darwin/arm64", b)
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// progressInterval is how often upload progress is reported.
+const progressInterval = 2 * time.Second
+
+// progressSmoothing is the weight of the latest throughput sample in the
+// smoothed throughput an ETA is based on.
+const progressSmoothing = 0.3
+
+// uploadProgress tracks the combined progress of the uploads to all the
+// instances of a put, which is reported periodically (see every).
+type uploadProgress struct {
+	total int64 // bytes to upload, or 0 if unknown
+
+	mu       sync.Mutex
+	done     int64     // bytes uploaded so far
+	rate     float64   // smoothed bytes per second
+	last     time.Time // time of the previous sample
+	lastDone int64     // done at the previous sample
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newUploadProgress returns an uploadProgress for uploads totalling total
+// bytes, or an unknown amount if it's 0.
+func newUploadProgress(total int64) *uploadProgress {
+	return &uploadProgress{
+		total: total,
+		last:  time.Now(),
+		stop:  make(chan struct{}),
+	}
+}
+
+// every calls report every interval until Stop is called.
+func (p *uploadProgress) every(interval time.Duration, report func(now time.Time)) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case now := <-t.C:
+				report(now)
+			case <-p.stop:
+				return
+			}
+		}
+	}()
+}
+
+// reportToStderr reports progress to stderr every progressInterval, with
+// an ETA if the total is known.
+func (p *uploadProgress) reportToStderr() {
+	p.every(progressInterval, func(now time.Time) {
+		fmt.Fprintf(os.Stderr, "# progress: %s\n", p.sample(now))
+	})
+}
+
+// checkpointToStdout prints a timestamped line of the bytes uploaded to
+// dest so far to stdout every interval. Unlike reportToStderr, it's meant
+// for logs that aren't watched live, such as those of CI.
+func (p *uploadProgress) checkpointToStdout(interval time.Duration, dest string) {
+	p.every(interval, func(now time.Time) {
+		p.mu.Lock()
+		s := formatBytes(p.done)
+		if p.total > 0 {
+			s += " of " + formatBytes(p.total)
+		}
+		p.mu.Unlock()
+		fmt.Printf("%s uploaded %s to %s\n", now.UTC().Format(time.RFC3339), s, dest)
+	})
+}
+
+// Stop stops reporting progress.
+func (p *uploadProgress) Stop() {
+	close(p.stop)
+	p.wg.Wait()
+}
+
+// reader returns a reader which reads from r and counts the bytes read
+// as uploaded.
+func (p *uploadProgress) reader(r io.Reader) io.Reader {
+	return &progressReader{r: r, p: p}
+}
+
+type progressReader struct {
+	r io.Reader
+	p *uploadProgress
+}
+
+func (pr *progressReader) Read(b []byte) (int, error) {
+	n, err := pr.r.Read(b)
+	pr.p.mu.Lock()
+	pr.p.done += int64(n)
+	pr.p.mu.Unlock()
+	return n, err
+}
+
+// sample updates the smoothed throughput at time now, and describes the
+// progress so far.
+func (p *uploadProgress) sample(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if elapsed := now.Sub(p.last).Seconds(); elapsed > 0 {
+		current := float64(p.done-p.lastDone) / elapsed
+		if p.lastDone == 0 && p.rate == 0 {
+			p.rate = current
+		} else {
+			p.rate = progressSmoothing*current + (1-progressSmoothing)*p.rate
+		}
+		p.last, p.lastDone = now, p.done
+	}
+	s := formatBytes(p.done)
+	if p.total > 0 {
+		s += fmt.Sprintf(" of %s (%d%%)", formatBytes(p.total), p.done*100/p.total)
+	}
+	s += fmt.Sprintf(", %s/s", formatBytes(int64(p.rate)))
+	if p.total > 0 && p.rate > 0 && p.done < p.total {
+		eta := time.Duration(float64(p.total-p.done) / p.rate * float64(time.Second))
+		s += fmt.Sprintf(", ETA %s", eta.Round(time.Second))
+	}
+	return s
+}
+
+// formatBytes formats n bytes in binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
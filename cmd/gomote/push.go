@@ -323,7 +323,7 @@ func doPush(ctx context.Context, name, goroot string, dryRun, detailedProgress b
 		if err != nil {
 			return fmt.Errorf("unable to request credentials for a file upload: %w", err)
 		}
-		if err := uploadToGCS(ctx, resp.GetFields(), tgz, resp.GetObjectName(), resp.GetUrl()); err != nil {
+		if err := uploadToGCS(ctx, resp.GetFields(), tgz, resp.GetObjectName(), resp.GetUrl(), false); err != nil {
 			return fmt.Errorf("unable to upload file to GCS: %w", err)
 		}
 		if _, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
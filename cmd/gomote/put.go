@@ -8,22 +8,33 @@ import (
 	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"flag"
 	"fmt"
+	"hash"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
+	"golang.org/x/build/buildlet"
 	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/build/internal/gomote/sealed"
 	"golang.org/x/build/tarutil"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // putTar a .tar.gz
@@ -31,87 +42,401 @@ func putTar(args []string) error {
 	fs := flag.NewFlagSet("put", flag.ContinueOnError)
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "puttar usage: gomote puttar [put-opts] [instance] <source>")
+		fmt.Fprintln(os.Stderr, "              gomote puttar [put-opts] -source-list <file> [instance]")
 		fmt.Fprintln(os.Stderr)
 		fmt.Fprintln(os.Stderr, "<source> may be one of:")
 		fmt.Fprintln(os.Stderr, "- A path to a local .tar.gz file.")
-		fmt.Fprintln(os.Stderr, "- A URL that points at a .tar.gz file.")
-		fmt.Fprintln(os.Stderr, "- The '-' character to indicate a .tar.gz file passed via stdin.")
+		fmt.Fprintln(os.Stderr, "- A path to a local directory, which is packaged into a .tar.gz (see -source-dir, -use-gitignore, -exclude, and -eol).")
+		fmt.Fprintln(os.Stderr, "- A path to a local .deb or .rpm package, whose installed files are extracted (without package metadata).")
+		fmt.Fprintln(os.Stderr, "- A path to a local .zip file, which is converted to a .tar.gz.")
+		fmt.Fprintln(os.Stderr, "- A URL that points at a .tar.gz file, or at a .zip file, which is downloaded and converted locally.")
+		fmt.Fprintln(os.Stderr, "- The '-' character to indicate a .tar.gz or .zip file passed via stdin.")
 		fmt.Fprintln(os.Stderr, "- Git hash (min 7 characters) for the Go repository (extract a .tar.gz of the repository at that commit w/o history)")
 		fmt.Fprintln(os.Stderr)
-		fmt.Fprintln(os.Stderr, "Instance name is optional if a group is specified.")
+		fmt.Fprintln(os.Stderr, "With -source-list, each source in the file is extracted in turn on each instance,")
+		fmt.Fprintln(os.Stderr, "so later sources overlay earlier ones. Blank lines and lines starting with # are ignored.")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Instance name is optional if a group or -instances-matching is specified.")
 		fs.PrintDefaults()
 		os.Exit(1)
 	}
 	var dir string
 	fs.StringVar(&dir, "dir", "", "relative directory from buildlet's work dir to extra tarball into")
+	var sourceDir string
+	fs.StringVar(&sourceDir, "source-dir", "", "when the source is a local directory, only package the subtree at this path relative to it")
+	var sourceList string
+	fs.StringVar(&sourceList, "source-list", "", "file, or - for stdin, listing sources to extract in order instead of a <source> argument, one \"<source> [<dir>]\" per line; a dir overrides -dir")
+	var merge bool
+	fs.BoolVar(&merge, "merge", false, "merge into the existing contents of -dir, keeping files not in the tarball (the default)")
+	var opts putOptions
+	fs.BoolVar(&opts.clean, "clean", false, "remove -dir, which must be a directory below the work directory, and all of its contents before extracting the tarball")
+	fs.BoolVar(&opts.requireEmpty, "require-clean-dir", false, "fail, listing what's there, unless -dir is empty or doesn't exist on each instance before extracting the tarball")
+	fs.Func("tar-format", "format of the headers of tarballs built by gomote, from a directory, package, zip file, -transform, or git hash: gnu, pax, or ustar (default is picked per entry by archive/tar)", func(s string) (err error) {
+		opts.tarFormat, err = parseTarFormat(s)
+		return err
+	})
+	var filter dirFilter
+	fs.BoolVar(&filter.useGitignore, "use-gitignore", false, "when the source is a local directory, leave out .git and the paths ignored by .gitignore files in it")
+	fs.Func("exclude", "when the source is a local directory, leave out the paths matching this .gitignore-style pattern, even if a .gitignore file re-includes them; may be repeated", func(s string) error {
+		p, err := parseExcludePattern(s)
+		if err != nil {
+			return err
+		}
+		filter.excludes = append(filter.excludes, p)
+		return nil
+	})
+	fs.BoolVar(&opts.checkHealth, "check-health", false, "before writing to each instance, warn if it looks saturated (see -max-load-per-cpu and -min-free-memory); only Linux instances are checked")
+	fs.BoolVar(&opts.waitHealthy, "wait-healthy", false, "like -check-health, but wait until each saturated instance recovers before writing to it")
+	fs.Float64Var(&opts.maxLoadPerCPU, "max-load-per-cpu", defaultMaxLoadPerCPU, "with -check-health or -wait-healthy, the one-minute load average per CPU above which an instance is saturated")
+	opts.minFreeMemory = defaultMinFreeMemory
+	fs.Func("min-free-memory", "with -check-health or -wait-healthy, the available memory, such as 512M or 2G, below which an instance is saturated (default 512M)", func(s string) (err error) {
+		opts.minFreeMemory, err = parseByteSize(s)
+		return err
+	})
+	var eol string
+	fs.StringVar(&eol, "eol", "", "when the source is a local directory, normalize the line endings of its text files to crlf or lf, such as for tests on Windows; files with a NUL in their first 8000 bytes are treated as binary and left untouched")
+	var textExts, binaryExts string
+	fs.StringVar(&textExts, "text-ext", "", "with -eol, comma-separated file extensions, such as .bat,.cmd, of files always treated as text")
+	fs.StringVar(&binaryExts, "binary-ext", "", "with -eol, comma-separated file extensions of files never treated as text")
+	opts.registerFlags(fs)
 
 	fs.Parse(args)
+	if filter.useGitignore || len(filter.excludes) > 0 {
+		opts.dirFilter = &filter
+	}
+	if eol != "" {
+		crlf, err := parseEOL(eol)
+		if err != nil {
+			return err
+		}
+		opts.eol = &eolConverter{
+			crlf:       crlf,
+			textExts:   parseExtList(textExts),
+			binaryExts: parseExtList(binaryExts),
+		}
+	} else if textExts != "" || binaryExts != "" {
+		return fmt.Errorf("-text-ext and -binary-ext require -eol")
+	}
+	if merge && opts.clean {
+		return fmt.Errorf("-merge and -clean are mutually exclusive")
+	}
+	if opts.clean && sourceList != "" {
+		// Each source would remove what the ones before it extracted.
+		return fmt.Errorf("-clean and -source-list are mutually exclusive")
+	}
+	if opts.requireEmpty {
+		switch {
+		case merge:
+			return fmt.Errorf("-merge and -require-clean-dir are mutually exclusive")
+		case opts.clean:
+			return fmt.Errorf("-clean and -require-clean-dir are mutually exclusive")
+		case dir == "":
+			return fmt.Errorf("-require-clean-dir requires -dir")
+		case sourceList != "":
+			// Each source would find what the ones before it extracted.
+			return fmt.Errorf("-require-clean-dir and -source-list are mutually exclusive")
+		}
+	}
+	if err := opts.open(); err != nil {
+		return err
+	}
+	defer opts.close()
 
-	// Parse arguments.
+	// Parse arguments. With -source-list, the sources aren't arguments.
+	nsrc := 1
+	if sourceList != "" {
+		nsrc = 0
+	}
 	var putSet []string
 	var src string
-	switch fs.NArg() {
-	case 1:
+	switch {
+	case opts.instancesMatching != "":
+		if fs.NArg() != nsrc {
+			if nsrc == 0 {
+				fmt.Fprintln(os.Stderr, "error: want no arguments with -instances-matching and -source-list")
+			} else {
+				fmt.Fprintln(os.Stderr, "error: want only a source with -instances-matching")
+			}
+			fs.Usage()
+		}
+		var err error
+		putSet, err = opts.matchingInstances(context.Background())
+		if err != nil {
+			return err
+		}
+	case fs.NArg() == nsrc:
 		// Must be just the source, so we need an active group.
 		if activeGroup == nil {
-			fmt.Fprintln(os.Stderr, "no active group found; need an active group with only 1 argument")
+			fmt.Fprintln(os.Stderr, "no active group found; need an active group without an instance argument")
 			fs.Usage()
 		}
 		for _, inst := range activeGroup.Instances {
 			putSet = append(putSet, inst)
 		}
-		src = fs.Arg(0)
-	case 2:
+	case fs.NArg() == nsrc+1:
 		// Instance and source is specified.
 		putSet = []string{fs.Arg(0)}
-		src = fs.Arg(1)
-	case 0:
+	case fs.NArg() < nsrc:
 		fmt.Fprintln(os.Stderr, "error: not enough arguments")
 		fs.Usage()
 	default:
 		fmt.Fprintln(os.Stderr, "error: too many arguments")
 		fs.Usage()
 	}
+	if nsrc == 1 {
+		src = fs.Arg(fs.NArg() - 1)
+	}
+	if opts.leaderOnly || opts.leader != "" {
+		leaderSet, err := opts.leaderSet(putSet)
+		if err != nil {
+			return err
+		}
+		putSet = leaderSet
+	}
+	if opts.listOnly {
+		printInstances(putSet)
+		return nil
+	}
 
-	// Interpret source.
+	// Interpret sources.
+	var sources []*tarSource
+	if sourceList != "" {
+		entries, err := readSourceList(sourceList, dir)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			s, err := opts.tarSource(e.src, e.dir, sourceDir)
+			if err != nil {
+				return fmt.Errorf("%s: %w", e.pos, err)
+			}
+			sources = append(sources, s)
+		}
+	} else {
+		s, err := opts.tarSource(src, dir, sourceDir)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, s)
+	}
+	if opts.platform != "" {
+		for _, s := range sources {
+			if s.open == nil {
+				return errors.New("-platform requires a local source")
+			}
+			rc, err := s.open()
+			if err != nil {
+				return err
+			}
+			bin, err := tarGzBinary(rc)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("inspecting source: %w", err)
+			}
+			if err := opts.checkPlatform(context.Background(), putSet, bin); err != nil {
+				return err
+			}
+		}
+	}
+	if opts.detectDuplicates || opts.strictDuplicates {
+		if err := opts.checkDuplicates(putSet, sources); err != nil {
+			return err
+		}
+	}
+	if opts.tee != "" && !opts.stdinRead {
+		return errors.New("-tee requires the source to be -")
+	}
+	var srcSize int64
+	for _, s := range sources {
+		write, err := opts.resumable(s.src, s.dir, s.digest, opts.hooked(s.src, s.dir, s.write))
+		if err != nil {
+			return err
+		}
+		s.write = opts.recorded(s.src, s.dir, opts.healthGated(write))
+		srcSize += s.size
+	}
+	putTarFn := sources[0].write
+	if sourceList != "" {
+		// Extract the sources in order on each instance, so that later
+		// ones overlay earlier ones, stopping at the first failure.
+		putTarFn = func(ctx context.Context, inst string) error {
+			for _, s := range sources {
+				if err := s.write(ctx, inst); err != nil {
+					return fmt.Errorf("%s: %w", s.src, err)
+				}
+			}
+			return nil
+		}
+	}
+	putTarFn = opts.keptGoing(putTarFn)
+	opts.startProgress(srcSize*int64(len(putSet)), putSet)
+	eg, ctx := errgroup.WithContext(context.Background())
+	eg.SetLimit(putJobs(opts.jobs, len(putSet)))
+	for _, inst := range putSet {
+		inst := inst
+		eg.Go(func() error {
+			return putTarFn(ctx, inst)
+		})
+	}
+	return opts.finish(eg.Wait())
+}
+
+// tarSource is a source for puttar, interpreted by putOptions.tarSource.
+type tarSource struct {
+	src, dir string
+	write    func(ctx context.Context, inst string) error
+	digest   func() (string, error)        // identifies the source's contents for -resume-token
+	open     func() (io.ReadCloser, error) // opens the .tar.gz, if it's local
+	size     int64                         // size of the .tar.gz, if it's uploaded from here
+}
+
+// tarSource interprets src, a source as described in puttar's usage, to be
+// extracted into dir on each instance.
+func (o *putOptions) tarSource(src, dir, sourceDir string) (*tarSource, error) {
 	var putTarFn func(ctx context.Context, inst string) error
+	var digest func() (string, error)      // identifies the source's contents for -resume-token
+	var open func() (io.ReadCloser, error) // opens the .tar.gz, if it's local
+	var srcSize int64                      // size of the .tar.gz, if it's uploaded from here
+	var isDirSource bool
 	if src == "-" {
 		// We might have multiple readers, so slurp up STDIN
 		// and store it, then hand out bytes.Readers to everyone.
-		var buf bytes.Buffer
-		_, err := io.Copy(&buf, os.Stdin)
+		sharedTarBuf, err := o.readStdin()
 		if err != nil {
-			return fmt.Errorf("reading stdin: %w", err)
+			return nil, err
+		}
+		if format := packageFormat("", sharedTarBuf); format != "" {
+			tgz, err := packagePayloadTarGz(bytes.NewReader(sharedTarBuf), format, o.tarFormat, os.Stderr)
+			if err != nil {
+				return nil, fmt.Errorf("stdin: %w", err)
+			}
+			sharedTarBuf = tgz.Bytes()
+		} else if isZip(sharedTarBuf) {
+			tgz, err := zipTarGz(sharedTarBuf, o.tarFormat)
+			if err != nil {
+				return nil, fmt.Errorf("stdin: %w", err)
+			}
+			sharedTarBuf = tgz.Bytes()
 		}
-		sharedTarBuf := buf.Bytes()
 		putTarFn = func(ctx context.Context, inst string) error {
-			return doPutTar(ctx, inst, dir, bytes.NewReader(sharedTarBuf))
+			return doPutTar(ctx, inst, dir, bytes.NewReader(sharedTarBuf), o)
 		}
+		digest = func() (string, error) { return bytesDigest(sharedTarBuf), nil }
+		srcSize = int64(len(sharedTarBuf))
+		open = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(sharedTarBuf)), nil }
 	} else {
 		u, err := url.Parse(src)
 		if err != nil {
 			// The URL parser should technically accept any of these, so the fact that
 			// we failed means its *very* malformed.
-			return fmt.Errorf("malformed source: not a path, a URL, -, or a git hash")
+			return nil, fmt.Errorf("malformed source: not a path, a URL, -, or a git hash")
 		}
-		if u.Scheme != "" || u.Host != "" {
+		if (u.Scheme != "" || u.Host != "") && strings.HasSuffix(u.Path, ".zip") {
+			// A URL for a zip file. Instances only extract tarballs,
+			// so download and convert it once, and share the result
+			// between all the instances.
+			data, err := fetchZip(context.Background(), u.String())
+			if err != nil {
+				return nil, err
+			}
+			tgz, err := zipTarGz(data, o.tarFormat)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", src, err)
+			}
+			sharedTarBuf := tgz.Bytes()
+			putTarFn = func(ctx context.Context, inst string) error {
+				return doPutTar(ctx, inst, dir, bytes.NewReader(sharedTarBuf), o)
+			}
+			digest = func() (string, error) { return bytesDigest(sharedTarBuf), nil }
+			srcSize = int64(len(sharedTarBuf))
+			open = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(sharedTarBuf)), nil }
+		} else if u.Scheme != "" || u.Host != "" {
 			// Probably a real URL.
 			putTarFn = func(ctx context.Context, inst string) error {
-				return doPutTarURL(ctx, inst, dir, u.String())
+				return doPutTarURL(ctx, inst, dir, u.String(), o)
 			}
+			// The instance fetches the URL itself, so its contents
+			// can't be hashed here.
+			digest = func() (string, error) { return u.String(), nil }
 		} else {
 			// Probably a path. Check if it exists.
-			_, err := os.Stat(src)
+			fi, err := os.Stat(src)
 			if os.IsNotExist(err) {
 				// It must be a git hash. Check if this actually matches a git hash.
 				if len(src) < 7 || len(src) > 40 || regexp.MustCompile("[^a-f0-9]").MatchString(src) {
-					return fmt.Errorf("malformed source: not a path, a URL, -, or a git hash")
+					return nil, fmt.Errorf("malformed source: not a path, a URL, -, or a git hash")
 				}
 				putTarFn = func(ctx context.Context, inst string) error {
-					return doPutTarGoRev(ctx, inst, dir, src)
+					return doPutTarGoRev(ctx, inst, dir, src, o)
 				}
+				digest = func() (string, error) { return src, nil }
 			} else if err != nil {
-				return fmt.Errorf("failed to stat %q: %w", src, err)
+				return nil, fmt.Errorf("failed to stat %q: %w", src, err)
+			} else if fi.IsDir() {
+				// It's a directory. Package it up once and share
+				// the result between all the instances.
+				isDirSource = true
+				root := src
+				if sourceDir != "" {
+					root = filepath.Join(src, filepath.FromSlash(sourceDir))
+					if fi, err := os.Stat(root); err != nil {
+						return nil, fmt.Errorf("source subtree: %w", err)
+					} else if !fi.IsDir() {
+						return nil, fmt.Errorf("source subtree %q is not a directory", root)
+					}
+				}
+				tgz, err := tarGzDir(root, o.tarFormat, o.dirFilter, o.eol)
+				if err != nil {
+					return nil, err
+				}
+				sharedTarBuf := tgz.Bytes()
+				putTarFn = func(ctx context.Context, inst string) error {
+					return doPutTar(ctx, inst, dir, bytes.NewReader(sharedTarBuf), o)
+				}
+				digest = func() (string, error) { return bytesDigest(sharedTarBuf), nil }
+				srcSize = int64(len(sharedTarBuf))
+				open = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(sharedTarBuf)), nil }
+			} else if format, err := localPackageFormat(src); err != nil {
+				return nil, err
+			} else if format != "" {
+				// It's a .deb or .rpm. Extract the payload once and
+				// share it between all the instances.
+				f, err := os.Open(src)
+				if err != nil {
+					return nil, fmt.Errorf("opening %q: %w", src, err)
+				}
+				tgz, err := packagePayloadTarGz(f, format, o.tarFormat, os.Stderr)
+				f.Close()
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", src, err)
+				}
+				sharedTarBuf := tgz.Bytes()
+				putTarFn = func(ctx context.Context, inst string) error {
+					return doPutTar(ctx, inst, dir, bytes.NewReader(sharedTarBuf), o)
+				}
+				digest = func() (string, error) { return bytesDigest(sharedTarBuf), nil }
+				srcSize = int64(len(sharedTarBuf))
+				open = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(sharedTarBuf)), nil }
+			} else if zipped, err := isZipFile(src); err != nil {
+				return nil, err
+			} else if zipped {
+				// It's a .zip. Convert it once and share the result
+				// between all the instances.
+				data, err := os.ReadFile(src)
+				if err != nil {
+					return nil, err
+				}
+				tgz, err := zipTarGz(data, o.tarFormat)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", src, err)
+				}
+				sharedTarBuf := tgz.Bytes()
+				putTarFn = func(ctx context.Context, inst string) error {
+					return doPutTar(ctx, inst, dir, bytes.NewReader(sharedTarBuf), o)
+				}
+				digest = func() (string, error) { return bytesDigest(sharedTarBuf), nil }
+				srcSize = int64(len(sharedTarBuf))
+				open = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(sharedTarBuf)), nil }
 			} else {
 				// It's a path.
 				putTarFn = func(ctx context.Context, inst string) error {
@@ -120,84 +445,755 @@ func putTar(args []string) error {
 						return fmt.Errorf("opening %q: %w", src, err)
 					}
 					defer f.Close()
-					return doPutTar(ctx, inst, dir, f)
+					return doPutTar(ctx, inst, dir, f, o)
 				}
+				digest = func() (string, error) { return fileDigest(src) }
+				srcSize = fi.Size()
+				open = func() (io.ReadCloser, error) { return os.Open(src) }
 			}
 		}
 	}
-	eg, ctx := errgroup.WithContext(context.Background())
-	for _, inst := range putSet {
-		inst := inst
-		eg.Go(func() error {
-			return putTarFn(ctx, inst)
+	if sourceDir != "" && !isDirSource {
+		return nil, fmt.Errorf("-source-dir requires the source to be a local directory")
+	}
+	if o.dirFilter != nil && !isDirSource {
+		return nil, fmt.Errorf("-use-gitignore and -exclude require the source to be a local directory")
+	}
+	if o.eol != nil && !isDirSource {
+		return nil, fmt.Errorf("-eol requires the source to be a local directory")
+	}
+	if o.encrypt && open == nil {
+		return nil, errors.New("-encrypt requires a local source")
+	}
+	if o.transform != "" {
+		srcSize = 0 // unknown until transformed
+		if open == nil {
+			return nil, errors.New("-transform requires a local source")
+		}
+		putTarFn = func(ctx context.Context, inst string) error {
+			rc, err := open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			tgz, err := o.transformTarGz(ctx, inst, rc)
+			if err != nil {
+				return err
+			}
+			return doPutTar(ctx, inst, dir, tgz, o)
+		}
+	}
+	return &tarSource{
+		src:    src,
+		dir:    dir,
+		write:  putTarFn,
+		digest: digest,
+		open:   open,
+		size:   srcSize,
+	}, nil
+}
+
+func doPutTarURL(ctx context.Context, name, dir, tarURL string, opts *putOptions) error {
+	client := gomoteServerClient(ctx)
+	err := writeWithRetry(ctx, opts.tarWriteIdempotent(), func() error {
+		_, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
+			GomoteId:              name,
+			Directory:             dir,
+			Url:                   tarURL,
+			CleanDirectory:        opts.clean,
+			RequireEmptyDirectory: opts.requireEmpty,
+			BuildId:               opts.buildID,
+		})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("unable to write tar to instance: %w", err)
+	}
+	return nil
+}
+
+func doPutTarGoRev(ctx context.Context, name, dir, rev string, opts *putOptions) error {
+	tarURL := "https://go.googlesource.com/go/+archive/" + rev + ".tar.gz"
+	if err := doPutTarURL(ctx, name, dir, tarURL, opts); err != nil {
+		return err
+	}
+
+	// Put a VERSION file there too, to avoid git usage.
+	// It's merged into the tree written above, so it never cleans.
+	version := strings.NewReader("devel " + rev)
+	vh := &tar.Header{
+		Name: "VERSION",
+		Mode: 0644,
+		Size: int64(version.Len()),
+	}
+	setTarFormat(vh, opts.tarFormat)
+	var vtar tarutil.FileList
+	vtar.AddRegular(vh, int64(version.Len()), version)
+	tgz := vtar.TarGz()
+	defer tgz.Close()
+
+	client := gomoteServerClient(ctx)
+	resp, err := client.UploadFile(ctx, opts.uploadFileRequest())
+	if err != nil {
+		return fmt.Errorf("unable to request credentials for a file upload: %w", err)
+	}
+	if err := opts.checkUploadResponse(resp); err != nil {
+		return err
+	}
+	if err := opts.upload(ctx, resp, tgz, resp.GetObjectName()); err != nil {
+		return fmt.Errorf("unable to upload version file to GCS: %w", err)
+	}
+	objURL := opts.objectURL(resp)
+	if err := opts.mirror(ctx, client, objURL); err != nil {
+		return err
+	}
+	if err := writeWithRetry(ctx, true, func() error {
+		_, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
+			GomoteId:  name,
+			Directory: dir,
+			Url:       objURL,
+			BuildId:   opts.buildID,
+		})
+		return err
+	}); err != nil {
+		return fmt.Errorf("unable to write tar to instance: %w", err)
+	}
+	return nil
+}
+
+func doPutTar(ctx context.Context, name, dir string, tgz io.Reader, opts *putOptions) error {
+	client := gomoteServerClient(ctx)
+	if opts.encryptionKey != nil {
+		if err := opts.setDecryptionKey(ctx, client, name); err != nil {
+			return err
+		}
+	}
+	resp, err := client.UploadFile(ctx, opts.uploadFileRequest())
+	if err != nil {
+		return fmt.Errorf("unable to request credentials for a file upload: %w", err)
+	}
+	if err := opts.checkUploadResponse(resp); err != nil {
+		return err
+	}
+	h := opts.newChecksum()
+	var size byteCounter
+	body, err := opts.uploadBody(io.TeeReader(opts.progressReader(tgz), io.MultiWriter(h, &size)))
+	if err != nil {
+		return err
+	}
+	if err := opts.upload(ctx, resp, opts.throttle(ctx, body), resp.GetObjectName()); err != nil {
+		return fmt.Errorf("unable to upload file to GCS: %w", err)
+	}
+	objURL := opts.objectURL(resp)
+	if err := opts.mirror(ctx, client, objURL); err != nil {
+		return err
+	}
+	if err := writeWithRetry(ctx, opts.tarWriteIdempotent(), func() error {
+		_, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
+			GomoteId:              name,
+			Directory:             dir,
+			Url:                   objURL,
+			CleanDirectory:        opts.clean,
+			RequireEmptyDirectory: opts.requireEmpty,
+			Decrypt:               opts.encryptionKey != nil,
+			WaitForObject:         opts.waitForObject,
+			BuildId:               opts.buildID,
 		})
+		return err
+	}); err != nil {
+		return fmt.Errorf("unable to write tar to instance: %w", err)
+	}
+	if dir == "" {
+		dir = "."
+	}
+	return opts.recordUpload(h.Sum(nil), int64(size), dir, name)
+}
+
+// putOptions are the options shared by the put commands which control
+// how files are uploaded before being written to an instance.
+type putOptions struct {
+	// bucket is the bucket to upload files to. If empty, the server's
+	// default transfer bucket is used.
+	bucket string
+
+	// objectPrefix is the prefix under which the server names
+	// uploaded objects, if set.
+	objectPrefix string
+
+	// buildID is an opaque identifier of the build the uploads and
+	// writes are part of, recorded by the server, if set.
+	buildID string
+
+	// kmsKey is the resource name of a Cloud KMS key which uploaded
+	// objects are encrypted with by the bucket, if set.
+	kmsKey string
+
+	// mirrorTo is the bucket to which each uploaded object is
+	// copied within GCS, if set. A failed copy is only reported,
+	// unless mirrorStrict is set.
+	mirrorTo     string
+	mirrorStrict bool
+
+	// credentialsTTL is how long the credentials for each upload
+	// should remain valid, or zero for the server's default.
+	credentialsTTL time.Duration
+
+	// instancesMatching is a regular expression selecting the
+	// caller's instances to write to by name, instead of an instance
+	// argument or the active group, if set.
+	instancesMatching string
+
+	// leaderOnly is whether only one instance of a group is written
+	// to, for groups whose instances share a file system. The instance
+	// is leader if set, or else the first one.
+	leaderOnly bool
+	leader     string
+
+	// checksumFile is the path of a local file to which the digests
+	// of uploaded files are written, if set. checksumAlgo is the
+	// algorithm, or nil for the default of SHA-256.
+	checksumFile   string
+	checksumAlgo   *checksumAlgo
+	checksumAppend bool
+	checksums      *checksumManifest
+
+	// resumeFile is the path of a local file recording which writes
+	// completed, so that rerunning an interrupted put skips them.
+	resumeFile string
+	resume     *resumeToken
+
+	// platform is "auto" or a GOOS/GOARCH pair which executables in the
+	// source must be able to run on, if set.
+	platform string
+
+	// transform is a local command which the contents of each file are
+	// piped through, for each instance, before they're uploaded.
+	transform string
+
+	// splitLarge is the maximum size of each object a file is uploaded
+	// as, if it's not zero. Only put sets it.
+	splitLarge int64
+
+	// encrypt is whether uploads are sealed with encryptionKey, which
+	// the server opens them with as it writes them. keyedInstances holds
+	// the instances the server has been given the key for.
+	encrypt        bool
+	encryptionKey  []byte
+	keyedInstances sync.Map
+
+	// progress is whether the combined progress of uploads is
+	// reported. uploads tracks it while writes are under way.
+	progress bool
+	uploads  *uploadProgress
+
+	// uploadHosts limits the number of uploads in flight to each
+	// storage host.
+	uploadHosts hostLimiter
+
+	// throttlePerInstance is the maximum rate, in bytes per second,
+	// of the uploads for each instance, if positive.
+	throttlePerInstance int64
+
+	// checkpointInterval is how often a timestamped line of the
+	// combined progress of uploads is printed to stdout, if positive.
+	checkpointInterval time.Duration
+
+	// reportFile is the path of a local file, or "-" for stdout, to
+	// which a JSON report of the result for each instance is written.
+	reportFile string
+	report     *putReporter
+
+	// keepGoing is whether writes to the remaining instances continue
+	// after a write fails, and failures counts the writes that did.
+	keepGoing bool
+	failures  int32 // accessed atomically
+
+	// detectDuplicates is whether destinations which would be written
+	// more than once are reported, and strictDuplicates whether they
+	// are an error rather than a warning.
+	detectDuplicates bool
+	strictDuplicates bool
+
+	// afterUploadHook is a local command run after each successful
+	// write to an instance, and hookStrict whether its failure fails
+	// the write. hookSums holds the digest of the latest upload to each
+	// instance, for the hook.
+	afterUploadHook string
+	hookStrict      bool
+	hookMu          sync.Mutex
+	hookSums        map[string]string
+
+	// waitForObject is whether the server waits for each uploaded
+	// object to be readable before writing it.
+	waitForObject bool
+
+	// tee is the path of a local file to which stdin is copied when
+	// it's the source, if set. stdinRead is whether it was.
+	tee       string
+	stdinRead bool
+
+	// verboseHTTP is whether the requests uploading files and their
+	// responses are logged, with credentials redacted.
+	verboseHTTP bool
+
+	// listOnly is whether the instances which would be written to are
+	// printed instead.
+	listOnly bool
+
+	// jobs is the maximum number of instances written to at once.
+	// If zero, putJobs picks a default.
+	jobs int
+
+	// clean is whether the destination directory of a tarball is
+	// removed before the tarball is extracted into it. Only puttar
+	// sets it.
+	clean bool
+
+	// requireEmpty is whether writing a tarball fails unless its
+	// destination directory is empty or doesn't exist. Only puttar
+	// sets it.
+	requireEmpty bool
+
+	// tarFormat is the format of the headers of tarballs built
+	// locally, or tar.FormatUnknown to let archive/tar pick. Only
+	// puttar sets it.
+	tarFormat tar.Format
+
+	// dirFilter, if non-nil, selects the paths packaged from a local
+	// directory source. Only puttar sets it.
+	dirFilter *dirFilter
+
+	// checkHealth is whether the health of each instance is checked
+	// before writing to it, and waitHealthy whether a saturated one is
+	// waited for. An instance is saturated if its load per CPU is above
+	// maxLoadPerCPU or it has less than minFreeMemory bytes available.
+	// Only puttar sets them.
+	checkHealth   bool
+	waitHealthy   bool
+	maxLoadPerCPU float64
+	minFreeMemory int64
+
+	// eol, if non-nil, normalizes the line endings of text files
+	// packaged from a local directory source. Only puttar sets it.
+	eol *eolConverter
+
+	// expandEnv is whether the server expands variables in the
+	// destination of a file. Only put sets it.
+	expandEnv bool
+
+	// verifyMode is whether the mode of a file is checked after it's
+	// written. Only put sets it.
+	verifyMode bool
+}
+
+func (o *putOptions) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.bucket, "gcs-bucket", "", "GCS bucket to upload files to before they are written to the instance; must be one the server allows uploads to (default is the server's transfer bucket); other object stores, such as S3-compatible ones, aren't supported")
+	fs.StringVar(&o.objectPrefix, "object-prefix", "", "prefix, such as \"user/session\", under which uploaded objects are named in the bucket; with -verbose-http, each object's URL is printed")
+	fs.StringVar(&o.buildID, "build-id", "", "opaque identifier, such as a CI build's ID, to tag uploads and writes with; the server logs it and put-status lists it")
+	fs.StringVar(&o.kmsKey, "gcs-kms-key", "", "resource name of a Cloud KMS key, \"projects/P/locations/L/keyRings/R/cryptoKeys/K\", for the bucket to encrypt uploads with (CMEK); unlike -encrypt, the server sees the plaintext")
+	fs.StringVar(&o.mirrorTo, "mirror-to", "", "bucket, such as a backup bucket, to copy each uploaded object to within GCS; it must be available for uploads on the server")
+	fs.BoolVar(&o.mirrorStrict, "mirror-strict", false, "with -mirror-to, fail the put if an object can't be copied, instead of warning")
+	fs.DurationVar(&o.credentialsTTL, "credentials-ttl", 0, "how long the signed URL for each upload remains valid, for large uploads over slow links; the server caps it (default is the server's, 10m)")
+	fs.StringVar(&o.instancesMatching, "instances-matching", "", "write to each of your instances whose name matches this regular expression, instead of an instance argument or the active group (see also -jobs)")
+	fs.BoolVar(&o.listOnly, "list-only", false, "print the instances that would be written to, one per line, after any group, -instances-matching, or -leader-only selection, and exit without writing")
+	fs.BoolVar(&o.leaderOnly, "leader-only", false, "write only to the first instance (see -leader), for groups whose instances share a file system")
+	fs.StringVar(&o.leader, "leader", "", "with -leader-only, the instance to write to instead of the first one; implies -leader-only")
+	fs.StringVar(&o.checksumFile, "checksum-file", "", "local file to write a \"<digest>  <destination>  <instance>\" line to for each upload streamed from this machine; digests are SHA-256 unless -checksum-algo is given")
+	fs.Func("checksum-algo", "digest algorithm for -checksum-file and -report-json, recorded with each digest as \"<algo>:<hex>\": sha1, sha256, or sha512 (default is unprefixed sha256)", func(s string) (err error) {
+		o.checksumAlgo, err = parseChecksumAlgo(s)
+		return err
+	})
+	fs.BoolVar(&o.checksumAppend, "checksum-append", false, "append to the -checksum-file instead of truncating it")
+	fs.StringVar(&o.resumeFile, "resume-token", "", "local file recording which writes completed; a rerun skips those whose source is unchanged, and the file is removed once all writes succeed")
+	fs.StringVar(&o.platform, "platform", "", "check that executables in a local source run on GOOS/GOARCH, or on each instance's platform if \"auto\"")
+	fs.StringVar(&o.transform, "transform", "", "local command to pipe the contents of each file through before uploading it, run for each instance with $GOMOTE_INSTANCE and $GOMOTE_FILE set; requires a local source")
+	fs.BoolVar(&o.encrypt, "encrypt", false, "encrypt uploads with the AES-256 key in $"+encryptionKeyEnv+" (64 hex digits), so the bucket only holds ciphertext; the key is given to the server once per instance, which decrypts uploads when writing them; requires a local source")
+	fs.BoolVar(&o.progress, "progress", false, "periodically report the combined progress of uploads from this machine, with an ETA when the total size is known")
+	fs.DurationVar(&o.checkpointInterval, "checkpoint-interval", 0, "print a timestamped line of the combined progress of uploads to stdout at this interval, such as 30s, for logs like CI's where -progress is noise")
+	fs.StringVar(&o.reportFile, "report-json", "", "local file, or - for stdout, to write a JSON report of the result of each write to an instance to, even if some writes fail")
+	fs.BoolVar(&o.keepGoing, "keep-going", false, "keep writing to the other instances after a write fails, and fail at the end")
+	fs.BoolVar(&o.detectDuplicates, "detect-duplicates", false, "warn about instances listed more than once and, for local tarballs, paths with more than one entry, even in different -source-list sources, where the last entry wins")
+	fs.BoolVar(&o.strictDuplicates, "strict", false, "like -detect-duplicates, but fail instead of warning")
+	fs.StringVar(&o.afterUploadHook, "after-upload-hook", "", "local command to run after each successful write to an instance, with $GOMOTE_INSTANCE, $GOMOTE_SOURCE, $GOMOTE_DESTINATION, and $GOMOTE_DIGEST set; a failing hook is only reported (see -hook-strict)")
+	fs.BoolVar(&o.hookStrict, "hook-strict", false, "fail a write if its -after-upload-hook fails")
+	fs.IntVar(&o.uploadHosts.n, "concurrency-per-host", 0, "if positive, the maximum number of uploads in flight to each storage host, which the uploads of many instances may share, to stay under its rate limits")
+	fs.Func("throttle-per-instance", "maximum rate of the uploads for each instance, in bytes per second, such as 512K or 2M, however many run at once (default unlimited)", func(s string) (err error) {
+		o.throttlePerInstance, err = parseByteSize(s)
+		return err
+	})
+	fs.BoolVar(&o.waitForObject, "wait-for-upload", false, "have the server wait briefly for each uploaded object to be readable before writing it, for writes which fail with the object not found just after its upload")
+	fs.StringVar(&o.tee, "tee", "", "when the source is -, also write what's read from stdin to this local file, to keep a copy of what was put")
+	fs.BoolVar(&o.verboseHTTP, "verbose-http", false, "log the HTTP requests uploading files and their responses, with credentials redacted")
+	registerJobsFlag(fs, &o.jobs)
+}
+
+// maxDefaultPutJobs caps the default number of instances written to at once,
+// so that putting to a large group doesn't start an upload per instance all at
+// the same time.
+const maxDefaultPutJobs = 8
+
+// registerJobsFlag registers the -jobs flag, which limits how many instances
+// are written to at once, on fs.
+func registerJobsFlag(fs *flag.FlagSet, jobs *int) {
+	fs.IntVar(jobs, "jobs", 0, fmt.Sprintf("maximum number of instances to write to at once (default is the number of instances, up to %d)", maxDefaultPutJobs))
+}
+
+// putJobs returns the number of instances to write to at once when writing
+// to n instances, given the value of the -jobs flag.
+func putJobs(jobs, n int) int {
+	if jobs > 0 {
+		return jobs
+	}
+	if n > maxDefaultPutJobs {
+		return maxDefaultPutJobs
+	}
+	if n < 1 {
+		return 1
+	}
+	return n
+}
+
+// open prepares any local resources needed by the options.
+// It must be called after the flags are parsed.
+func (o *putOptions) open() error {
+	if o.listOnly {
+		// Nothing will be written, so there's nothing to prepare.
+		return nil
+	}
+	if o.checksumFile != "" {
+		m, err := openChecksumManifest(o.checksumFile, o.checksumAppend)
+		if err != nil {
+			return err
+		}
+		o.checksums = m
+	}
+	if o.resumeFile != "" {
+		t, err := openResumeToken(o.resumeFile)
+		if err != nil {
+			return err
+		}
+		o.resume = t
+	}
+	if o.reportFile != "" {
+		o.report = newPutReporter(o.reportFile)
+	}
+	if o.encrypt {
+		key, err := loadEncryptionKey()
+		if err != nil {
+			return err
+		}
+		o.encryptionKey = key
+	}
+	return nil
+}
+
+// close releases the resources acquired by open.
+func (o *putOptions) close() {
+	if o.checksums != nil {
+		if err := o.checksums.Close(); err != nil {
+			fmt.Fprintf(os.Stderr, "closing checksum file: %v\n", err)
+		}
+	}
+	if o.resume != nil {
+		o.resume.Close()
+	}
+}
+
+// resumable returns a function which writes the source src with the given
+// digest to dst on an instance using write, unless a previous run using the
+// same resume token already did so. If no resume token is in use, it returns
+// write unchanged. The digest is computed only if it's needed.
+func (o *putOptions) resumable(src, dst string, digest func() (string, error), write func(ctx context.Context, inst string) error) (func(ctx context.Context, inst string) error, error) {
+	if o.resume == nil {
+		return write, nil
+	}
+	sum, err := digest()
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, inst string) error {
+		if o.resume.completed(sum, src, dst, inst) {
+			fmt.Fprintf(os.Stderr, "# %s: %s already written by a previous run; skipping\n", inst, dst)
+			return nil
+		}
+		if err := write(ctx, inst); err != nil {
+			return err
+		}
+		return o.resume.record(sum, src, dst, inst)
+	}, nil
+}
+
+// finishResume removes the resume token, if any, once every write has
+// completed.
+func (o *putOptions) finishResume() error {
+	if o.resume == nil {
+		return nil
+	}
+	t := o.resume
+	o.resume = nil
+	if err := t.remove(); err != nil {
+		return fmt.Errorf("removing resume token: %w", err)
+	}
+	return nil
+}
+
+// recordUpload records a successful upload of size bytes with the digest
+// sum to dst on inst, if a checksum file, report, or hook was requested.
+func (o *putOptions) recordUpload(sum []byte, size int64, dst, inst string) error {
+	o.recordHookSum(inst, o.formatChecksum(sum))
+	if o.report != nil {
+		o.report.recordUpload(inst, dst, size, o.checksumAlgo, sum)
+	}
+	if o.checksums == nil {
+		return nil
+	}
+	return o.checksums.record(o.formatChecksum(sum), dst, inst)
+}
+
+// newChecksum returns a hash for the -checksum-algo algorithm, which is
+// SHA-256 by default.
+func (o *putOptions) newChecksum() hash.Hash {
+	if o.checksumAlgo == nil {
+		return sha256.New()
+	}
+	return o.checksumAlgo.new()
+}
+
+// formatChecksum formats a digest from newChecksum. If -checksum-algo was
+// given, it's prefixed by the algorithm's name, as in "sha512:<hex>".
+func (o *putOptions) formatChecksum(sum []byte) string {
+	if o.checksumAlgo == nil {
+		return fmt.Sprintf("%x", sum)
+	}
+	return fmt.Sprintf("%s:%x", o.checksumAlgo.name, sum)
+}
+
+// recorded returns a function which writes src to dst on an instance using
+// write, and adds the result to the -report-json report, if any.
+func (o *putOptions) recorded(src, dst string, write func(ctx context.Context, inst string) error) func(ctx context.Context, inst string) error {
+	if o.report == nil {
+		return write
+	}
+	return func(ctx context.Context, inst string) error {
+		start := time.Now()
+		err := write(ctx, inst)
+		res := &putResult{
+			Instance:        inst,
+			Source:          src,
+			Destination:     dst,
+			DurationSeconds: time.Since(start).Seconds(),
+			Success:         err == nil,
+		}
+		if err != nil {
+			res.Error = err.Error()
+		}
+		o.report.add(res)
+		return err
+	}
+}
+
+// keptGoing returns write, except that with -keep-going, a failed write is
+// logged and counted instead of returned, so that the writes to other
+// instances carry on.
+func (o *putOptions) keptGoing(write func(ctx context.Context, inst string) error) func(ctx context.Context, inst string) error {
+	if !o.keepGoing {
+		return write
+	}
+	return func(ctx context.Context, inst string) error {
+		err := write(ctx, inst)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "# %s: %v\n", inst, err)
+			atomic.AddInt32(&o.failures, 1)
+			return nil
+		}
+		return err
+	}
+}
+
+// finish is called with the result of writing to every instance. It writes
+// the -report-json report, if any, and removes the resume token if every
+// write succeeded.
+func (o *putOptions) finish(err error) error {
+	if o.uploads != nil {
+		o.uploads.Stop()
+	}
+	if n := atomic.LoadInt32(&o.failures); err == nil && n > 0 {
+		err = fmt.Errorf("%d writes failed", n)
+	}
+	if o.report != nil {
+		if rerr := o.report.write(); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return o.finishResume()
+}
+
+// printInstances prints insts, one per line, for -list-only.
+func printInstances(insts []string) {
+	for _, inst := range insts {
+		fmt.Println(inst)
+	}
+}
+
+// matchingInstances returns the caller's instances whose names match the
+// -instances-matching regular expression. It's an error if none do.
+func (o *putOptions) matchingInstances(ctx context.Context) ([]string, error) {
+	re, err := regexp.Compile(o.instancesMatching)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -instances-matching: %w", err)
+	}
+	client := gomoteServerClient(ctx)
+	resp, err := client.ListInstances(ctx, &protos.ListInstancesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to list instances: %w", err)
+	}
+	var insts []string
+	for _, inst := range resp.GetInstances() {
+		if re.MatchString(inst.GetGomoteId()) {
+			insts = append(insts, inst.GetGomoteId())
+		}
+	}
+	if len(insts) == 0 {
+		return nil, fmt.Errorf("no instances match %q", o.instancesMatching)
 	}
-	return eg.Wait()
+	return insts, nil
 }
 
-func doPutTarURL(ctx context.Context, name, dir, tarURL string) error {
-	client := gomoteServerClient(ctx)
-	_, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
-		GomoteId:  name,
-		Directory: dir,
-		Url:       tarURL,
-	})
-	if err != nil {
-		return fmt.Errorf("unable to write tar to instance: %w", err)
+// startProgress starts reporting the progress of uploads to insts
+// totalling total bytes, or an unknown amount if it's 0, if -progress or
+// -checkpoint-interval is set.
+func (o *putOptions) startProgress(total int64, insts []string) {
+	if !o.progress && o.checkpointInterval <= 0 {
+		return
+	}
+	o.uploads = newUploadProgress(total)
+	if o.progress {
+		o.uploads.reportToStderr()
+	}
+	if o.checkpointInterval > 0 {
+		dest := fmt.Sprintf("%d instances", len(insts))
+		if len(insts) == 1 {
+			dest = insts[0]
+		}
+		o.uploads.checkpointToStdout(o.checkpointInterval, dest)
 	}
-	return nil
 }
 
-func doPutTarGoRev(ctx context.Context, name, dir, rev string) error {
-	tarURL := "https://go.googlesource.com/go/+archive/" + rev + ".tar.gz"
-	if err := doPutTarURL(ctx, name, dir, tarURL); err != nil {
-		return err
+// progressReader returns r, counting what's read from it as uploaded if
+// -progress or -checkpoint-interval is set.
+func (o *putOptions) progressReader(r io.Reader) io.Reader {
+	if o.uploads == nil {
+		return r
 	}
+	return o.uploads.reader(r)
+}
 
-	// Put a VERSION file there too, to avoid git usage.
-	version := strings.NewReader("devel " + rev)
-	var vtar tarutil.FileList
-	vtar.AddRegular(&tar.Header{
-		Name: "VERSION",
-		Mode: 0644,
-		Size: int64(version.Len()),
-	}, int64(version.Len()), version)
-	tgz := vtar.TarGz()
-	defer tgz.Close()
+// readStdin reads all of stdin, which is the source, and copies it to
+// the -tee file if there is one.
+func (o *putOptions) readStdin() ([]byte, error) {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, fmt.Errorf("reading stdin: %w", err)
+	}
+	o.stdinRead = true
+	if o.tee != "" {
+		if err := os.WriteFile(o.tee, data, 0666); err != nil {
+			return nil, fmt.Errorf("-tee: %w", err)
+		}
+	}
+	return data, nil
+}
 
-	client := gomoteServerClient(ctx)
-	resp, err := client.UploadFile(ctx, &protos.UploadFileRequest{})
+// uploadBody returns the body to upload for the contents r, which is r
+// itself unless -encrypt is set. The contents are sealed as they're read.
+func (o *putOptions) uploadBody(r io.Reader) (io.Reader, error) {
+	if o.encryptionKey == nil {
+		return r, nil
+	}
+	sr, err := sealed.NewSealer(r, o.encryptionKey)
 	if err != nil {
-		return fmt.Errorf("unable to request credentials for a file upload: %w", err)
+		return nil, fmt.Errorf("unable to encrypt upload: %w", err)
 	}
-	if err := uploadToGCS(ctx, resp.GetFields(), tgz, resp.GetObjectName(), resp.GetUrl()); err != nil {
-		return fmt.Errorf("unable to upload version file to GCS: %w", err)
+	return sr, nil
+}
+
+// leaderSet returns the instance in insts to write to with -leader-only,
+// and warns that the others are assumed to share its file system.
+func (o *putOptions) leaderSet(insts []string) ([]string, error) {
+	leader := o.leader
+	if leader == "" {
+		leader = insts[0]
+	} else {
+		found := false
+		for _, inst := range insts {
+			if inst == leader {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("-leader %q is not one of the instances being written to", leader)
+		}
 	}
-	if _, err = client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
-		GomoteId:  name,
-		Directory: dir,
-		Url:       fmt.Sprintf("%s%s", resp.GetUrl(), resp.GetObjectName()),
-	}); err != nil {
-		return fmt.Errorf("unable to write tar to instance: %w", err)
+	if len(insts) > 1 {
+		fmt.Fprintf(os.Stderr, "# -leader-only: writing only to %q, assuming the other %d instances share its file system\n", leader, len(insts)-1)
+	}
+	return []string{leader}, nil
+}
+
+// uploadFileRequest returns the request for credentials to upload a file.
+func (o *putOptions) uploadFileRequest() *protos.UploadFileRequest {
+	return &protos.UploadFileRequest{
+		Bucket:                o.bucket,
+		ObjectPrefix:          o.objectPrefix,
+		KmsKeyName:            o.kmsKey,
+		BuildId:               o.buildID,
+		CredentialsTtlSeconds: int64(o.credentialsTTL / time.Second),
+	}
+}
+
+// checkUploadResponse checks that the upload credentials in resp honor
+// the options, which a server predating them would silently ignore.
+func (o *putOptions) checkUploadResponse(resp *protos.UploadFileResponse) error {
+	if o.kmsKey != "" && resp.GetFields()["x-goog-encryption-kms-key-name"] != o.kmsKey {
+		return fmt.Errorf("the server didn't apply -gcs-kms-key %q to the upload; it may not support it", o.kmsKey)
 	}
 	return nil
 }
 
-func doPutTar(ctx context.Context, name, dir string, tgz io.Reader) error {
-	client := gomoteServerClient(ctx)
-	resp, err := client.UploadFile(ctx, &protos.UploadFileRequest{})
-	if err != nil {
-		return fmt.Errorf("unable to request credentials for a file upload: %w", err)
+// objectURL returns the URL of the object uploaded using the credentials
+// in resp, and prints it if -verbose-http is set.
+func (o *putOptions) objectURL(resp *protos.UploadFileResponse) string {
+	u := resp.GetUrl() + resp.GetObjectName()
+	if o.verboseHTTP {
+		fmt.Fprintf(os.Stderr, "# uploaded %s\n", redactURL(u))
 	}
-	if err := uploadToGCS(ctx, resp.GetFields(), tgz, resp.GetObjectName(), resp.GetUrl()); err != nil {
-		return fmt.Errorf("unable to upload file to GCS: %w", err)
+	return u
+}
+
+// mirror copies the uploaded object at url to the -mirror-to bucket,
+// if set. A failure is only reported on stderr, unless -mirror-strict
+// is set.
+func (o *putOptions) mirror(ctx context.Context, client protos.GomoteServiceClient, url string) error {
+	if o.mirrorTo == "" {
+		return nil
 	}
-	if _, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
-		GomoteId:  name,
-		Directory: dir,
-		Url:       fmt.Sprintf("%s%s", resp.GetUrl(), resp.GetObjectName()),
-	}); err != nil {
-		return fmt.Errorf("unable to write tar to instance: %w", err)
+	resp, err := client.MirrorObject(ctx, &protos.MirrorObjectRequest{
+		Url:               url,
+		DestinationBucket: o.mirrorTo,
+	})
+	if err != nil {
+		if o.mirrorStrict {
+			return fmt.Errorf("unable to mirror upload to bucket %q: %w", o.mirrorTo, err)
+		}
+		fmt.Fprintf(os.Stderr, "# warning: unable to mirror upload to bucket %q: %v\n", o.mirrorTo, err)
+		return nil
+	}
+	if o.verboseHTTP {
+		fmt.Fprintf(os.Stderr, "# mirrored to %s\n", redactURL(resp.GetUrl()))
 	}
 	return nil
 }
@@ -212,6 +1208,8 @@ func putBootstrap(args []string) error {
 		fs.PrintDefaults()
 		os.Exit(1)
 	}
+	var jobs int
+	registerJobsFlag(fs, &jobs)
 	fs.Parse(args)
 
 	var putSet []string
@@ -232,6 +1230,7 @@ func putBootstrap(args []string) error {
 	}
 
 	eg, ctx := errgroup.WithContext(context.Background())
+	eg.SetLimit(putJobs(jobs, len(putSet)))
 	for _, inst := range putSet {
 		inst := inst
 		eg.Go(func() error {
@@ -257,21 +1256,61 @@ func put(args []string) error {
 	fs.Usage = func() {
 		fmt.Fprintln(os.Stderr, "put usage: gomote put [put-opts] [instance] <source or '-' for stdin> [destination]")
 		fmt.Fprintln(os.Stderr)
-		fmt.Fprintln(os.Stderr, "Instance name is optional if a group is specified.")
+		fmt.Fprintln(os.Stderr, "A source which doesn't exist but contains *, ?, or [ is a pattern, expanded by gomote as by")
+		fmt.Fprintln(os.Stderr, "filepath.Glob, and each matching file is written to the destination directory.")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Instance name is optional if a group or -instances-matching is specified.")
 		fs.PrintDefaults()
 		os.Exit(1)
 	}
 	modeStr := fs.String("mode", "", "Unix file mode (octal); default to source file mode")
+	preserveTimes := fs.Bool("preserve-times", false, "set the modification time of the destination to that of the source file; no effect when the source is stdin")
+	allowEmptyGlob := fs.Bool("allow-empty-glob", false, "succeed without writing anything when a source pattern matches no files")
+	update := fs.Bool("update", false, "skip instances where the destination is at least as new as the local source file, like rsync -u, and report each one skipped")
+	var opts putOptions
+	fs.Func("split-large", "upload the source in parts of at most this size, such as 512M or 2G, which the server reassembles and checks, for files too large to upload as one object", func(s string) (err error) {
+		opts.splitLarge, err = parseByteSize(s)
+		return err
+	})
+	fs.BoolVar(&opts.expandEnv, "env-expand", false, "expand $WORKDIR, $GO_BUILDER_NAME, $GOOS, and $GOARCH in the destination on the server, for each instance")
+	fs.BoolVar(&opts.verifyMode, "verify-mode", false, "after writing, check that the destination's permission bits are the requested mode, which a umask or file system can change")
+	opts.registerFlags(fs)
 	fs.Parse(args)
 
 	if fs.NArg() == 0 {
 		fs.Usage()
 	}
+	if opts.verifyMode && opts.expandEnv {
+		return errors.New("-verify-mode and -env-expand are mutually exclusive")
+	}
+	if opts.splitLarge > 0 && opts.encrypt {
+		return errors.New("-split-large and -encrypt are mutually exclusive")
+	}
+	if *update && opts.expandEnv {
+		return errors.New("-update and -env-expand are mutually exclusive")
+	}
+	if err := opts.open(); err != nil {
+		return err
+	}
+	defer opts.close()
 
 	ctx := context.Background()
 	var putSet []string
 	var src, dst string
-	if err := doPing(ctx, fs.Arg(0)); instanceDoesNotExist(err) {
+	if opts.instancesMatching != "" {
+		var err error
+		putSet, err = opts.matchingInstances(ctx)
+		if err != nil {
+			return err
+		}
+		src = fs.Arg(0)
+		if fs.NArg() == 2 {
+			dst = fs.Arg(1)
+		} else if fs.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "error: too many arguments")
+			fs.Usage()
+		}
+	} else if err := doPing(ctx, fs.Arg(0)); instanceDoesNotExist(err) {
 		// When there's no active group, this is just an error.
 		if activeGroup == nil {
 			return fmt.Errorf("instance %q: %w", fs.Arg(0), err)
@@ -304,36 +1343,159 @@ func put(args []string) error {
 	} else {
 		return fmt.Errorf("checking instance %q: %w", fs.Arg(0), err)
 	}
-	if dst == "" {
-		if src == "-" {
-			return errors.New("must specify destination file name when source is standard input")
+	if opts.leaderOnly || opts.leader != "" {
+		leaderSet, err := opts.leaderSet(putSet)
+		if err != nil {
+			return err
 		}
-		dst = filepath.Base(src)
+		putSet = leaderSet
+	}
+	if opts.listOnly {
+		printInstances(putSet)
+		return nil
 	}
 
-	var mode os.FileMode = 0666
-	if *modeStr != "" {
-		modeInt, err := strconv.ParseInt(*modeStr, 8, 64)
+	// Interpret sources. A source which doesn't exist but has glob
+	// metacharacters is a pattern, and its matches are written to the
+	// directory dst.
+	flags := fileFlags{mode: *modeStr, preserveTimes: *preserveTimes, update: *update}
+	var sources []*fileSource
+	if src != "-" && isGlobPattern(src) {
+		matches, err := globFiles(src)
+		if err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			if !*allowEmptyGlob {
+				return fmt.Errorf("%q matches no files", src)
+			}
+			fmt.Fprintf(os.Stderr, "# %q matches no files; nothing to put\n", src)
+			return nil
+		}
+		written := make(map[string]string) // destination to source
+		for _, m := range matches {
+			d := path.Join(dst, filepath.Base(m))
+			if prev, ok := written[d]; ok {
+				return fmt.Errorf("%s and %s would both be written to %s", prev, m, d)
+			}
+			written[d] = m
+			s, err := opts.fileSource(ctx, putSet, m, d, flags)
+			if err != nil {
+				return fmt.Errorf("%s: %w", m, err)
+			}
+			sources = append(sources, s)
+		}
+	} else {
+		if dst == "" {
+			if src == "-" {
+				return errors.New("must specify destination file name when source is standard input")
+			}
+			dst = filepath.Base(src)
+		}
+		s, err := opts.fileSource(ctx, putSet, src, dst, flags)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, s)
+	}
+	if opts.tee != "" && !opts.stdinRead {
+		return errors.New("-tee requires the source to be -")
+	}
+	if opts.detectDuplicates || opts.strictDuplicates {
+		if err := opts.checkDuplicates(putSet, nil); err != nil {
+			return err
+		}
+	}
+	var srcSize int64
+	for _, s := range sources {
+		write, err := opts.resumable(s.src, s.dst, s.digest, opts.hooked(s.src, s.dst, s.write))
 		if err != nil {
 			return err
 		}
+		s.write = opts.recorded(s.src, s.dst, write)
+		srcSize += s.size
+	}
+	putFileFn := sources[0].write
+	if len(sources) > 1 {
+		// Write the matches in turn on each instance, stopping at the
+		// first failure.
+		putFileFn = func(ctx context.Context, inst string) error {
+			for _, s := range sources {
+				if err := s.write(ctx, inst); err != nil {
+					return fmt.Errorf("%s: %w", s.src, err)
+				}
+			}
+			return nil
+		}
+	}
+	putFileFn = opts.keptGoing(putFileFn)
+
+	opts.startProgress(srcSize*int64(len(putSet)), putSet)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(putJobs(opts.jobs, len(putSet)))
+	for _, inst := range putSet {
+		inst := inst
+		eg.Go(func() error {
+			return putFileFn(ctx, inst)
+		})
+	}
+	return opts.finish(eg.Wait())
+}
+
+// fileFlags are the flags of put which apply to each source.
+type fileFlags struct {
+	mode          string // -mode
+	preserveTimes bool   // -preserve-times
+	update        bool   // -update
+}
+
+// fileSource is a source of put.
+type fileSource struct {
+	src, dst string
+	write    func(ctx context.Context, inst string) error // writes the source to dst on an instance
+	digest   func() (string, error)                       // identifies the source's contents for -resume-token
+	size     int64                                        // size of the source, if known
+}
+
+// fileSource interprets src, a local file or "-" for stdin, which is
+// written to dst on the instances in putSet.
+func (o *putOptions) fileSource(ctx context.Context, putSet []string, src, dst string, flags fileFlags) (*fileSource, error) {
+	var mode os.FileMode = 0666
+	if flags.mode != "" {
+		modeInt, err := strconv.ParseInt(flags.mode, 8, 64)
+		if err != nil {
+			return nil, err
+		}
 		mode = os.FileMode(modeInt)
 		if !mode.IsRegular() {
-			return fmt.Errorf("bad mode: %v", mode)
+			return nil, fmt.Errorf("bad mode: %v", mode)
+		}
+	}
+
+	var mtime time.Time
+	if flags.preserveTimes && src != "-" {
+		fi, err := os.Stat(src)
+		if err != nil {
+			return nil, err
 		}
+		mtime = fi.ModTime()
 	}
 
 	var putFileFn func(context.Context, string) error
+	var digest func() (string, error)      // identifies the source's contents for -resume-token
+	var open func() (io.ReadCloser, error) // opens the source
+	var srcSize int64                      // size of the source
 	if src == "-" {
-		var buf bytes.Buffer
-		_, err := io.Copy(&buf, os.Stdin)
+		sharedFileBuf, err := o.readStdin()
 		if err != nil {
-			return fmt.Errorf("reading from stdin: %w", err)
+			return nil, err
 		}
-		sharedFileBuf := buf.Bytes()
 		putFileFn = func(ctx context.Context, inst string) error {
-			return doPutFile(ctx, inst, bytes.NewReader(sharedFileBuf), dst, mode)
+			return doPutFile(ctx, inst, bytes.NewReader(sharedFileBuf), dst, mode, mtime, o)
 		}
+		digest = func() (string, error) { return bytesDigest(sharedFileBuf), nil }
+		srcSize = int64(len(sharedFileBuf))
+		open = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(sharedFileBuf)), nil }
 	} else {
 		putFileFn = func(ctx context.Context, inst string) error {
 			f, err := os.Open(src)
@@ -342,50 +1504,303 @@ func put(args []string) error {
 			}
 			defer f.Close()
 
-			if *modeStr == "" {
+			if flags.mode == "" {
 				fi, err := f.Stat()
 				if err != nil {
 					return err
 				}
 				mode = fi.Mode()
 			}
-			return doPutFile(ctx, inst, f, dst, mode)
+			return doPutFile(ctx, inst, f, dst, mode, mtime, o)
+		}
+		digest = func() (string, error) { return fileDigest(src) }
+		open = func() (io.ReadCloser, error) { return os.Open(src) }
+		if fi, err := os.Stat(src); err == nil {
+			srcSize = fi.Size()
+		}
+	}
+	if o.transform != "" {
+		srcSize = 0 // unknown until transformed
+		if src != "-" && flags.mode == "" {
+			fi, err := os.Stat(src)
+			if err != nil {
+				return nil, err
+			}
+			mode = fi.Mode()
+		}
+		putFileFn = func(ctx context.Context, inst string) error {
+			rc, err := open()
+			if err != nil {
+				return err
+			}
+			defer rc.Close()
+			data, err := o.transformFile(ctx, inst, dst, rc)
+			if err != nil {
+				return err
+			}
+			return doPutFile(ctx, inst, bytes.NewReader(data), dst, mode, mtime, o)
 		}
 	}
+	if flags.update {
+		if src == "-" {
+			return nil, errors.New("-update requires a local source file")
+		}
+		fi, err := os.Stat(src)
+		if err != nil {
+			return nil, err
+		}
+		// The instance lists modification times to the second.
+		local := fi.ModTime().Truncate(time.Second)
+		write := putFileFn
+		putFileFn = func(ctx context.Context, inst string) error {
+			if remote, ok := remoteModTime(ctx, inst, dst); ok && !remote.Before(local) {
+				fmt.Fprintf(os.Stderr, "# %s: %s there is as new as the source (%s); skipping\n", inst, dst, remote.Format(time.RFC3339))
+				return nil
+			}
+			return write(ctx, inst)
+		}
+	}
+	if o.platform != "" {
+		rc, err := open()
+		if err != nil {
+			return nil, err
+		}
+		bin, err := readBinary(dst, rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("inspecting source: %w", err)
+		}
+		if err := o.checkPlatform(ctx, putSet, bin); err != nil {
+			return nil, err
+		}
+	}
+	return &fileSource{src: src, dst: dst, write: putFileFn, digest: digest, size: srcSize}, nil
+}
 
-	eg, ctx := errgroup.WithContext(ctx)
-	for _, inst := range putSet {
-		inst := inst
-		eg.Go(func() error {
-			return putFileFn(ctx, inst)
-		})
+// isGlobPattern reports whether src is a pattern for put to expand: one
+// with glob metacharacters which doesn't name an existing file.
+func isGlobPattern(src string) bool {
+	if !strings.ContainsAny(src, "*?[") {
+		return false
 	}
-	return eg.Wait()
+	_, err := os.Stat(src)
+	return os.IsNotExist(err)
+}
+
+// globFiles returns the files matching pattern, in lexical order, as
+// filepath.Glob does. Matching directories are skipped with a note.
+func globFiles(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("bad pattern %q: %w", pattern, err)
+	}
+	var files []string
+	for _, m := range matches {
+		fi, err := os.Stat(m)
+		if err != nil {
+			return nil, err
+		}
+		if fi.IsDir() {
+			fmt.Fprintf(os.Stderr, "# skipping directory %s\n", m)
+			continue
+		}
+		files = append(files, m)
+	}
+	return files, nil
 }
 
-func doPutFile(ctx context.Context, inst string, r io.Reader, dst string, mode os.FileMode) error {
+// doPutFile writes the contents of r to dst on inst with the given mode.
+// If mtime is not zero, it's set as the modification time of dst.
+func doPutFile(ctx context.Context, inst string, r io.Reader, dst string, mode os.FileMode, mtime time.Time, opts *putOptions) error {
 	client := gomoteServerClient(ctx)
-	resp, err := client.UploadFile(ctx, &protos.UploadFileRequest{})
+	if opts.encryptionKey != nil {
+		if err := opts.setDecryptionKey(ctx, client, inst); err != nil {
+			return err
+		}
+	}
+	h := opts.newChecksum()
+	var size byteCounter
+	req := &protos.WriteFileFromURLRequest{
+		GomoteId:      inst,
+		Filename:      dst,
+		Mode:          uint32(mode),
+		ExpandEnv:     opts.expandEnv,
+		Decrypt:       opts.encryptionKey != nil,
+		WaitForObject: opts.waitForObject,
+		BuildId:       opts.buildID,
+	}
+	if opts.splitLarge > 0 {
+		// The server checks that the parts are reassembled intact.
+		whole := sha256.New()
+		body := opts.throttle(ctx, io.TeeReader(opts.progressReader(r), io.MultiWriter(h, &size, whole)))
+		parts, err := opts.uploadParts(ctx, client, body, dst)
+		if err != nil {
+			return err
+		}
+		if len(parts) == 1 {
+			req.Url = parts[0]
+		} else {
+			req.PartUrls = parts
+			req.Sha256 = whole.Sum(nil)
+		}
+	} else {
+		resp, err := client.UploadFile(ctx, opts.uploadFileRequest())
+		if err != nil {
+			return fmt.Errorf("unable to request credentials for a file upload: %w", err)
+		}
+		if err := opts.checkUploadResponse(resp); err != nil {
+			return err
+		}
+		body, err := opts.uploadBody(io.TeeReader(opts.progressReader(r), io.MultiWriter(h, &size)))
+		if err != nil {
+			return err
+		}
+		if err := opts.upload(ctx, resp, opts.throttle(ctx, body), dst); err != nil {
+			return fmt.Errorf("unable to upload file to GCS: %w", err)
+		}
+		req.Url = opts.objectURL(resp)
+		if err := opts.mirror(ctx, client, req.Url); err != nil {
+			return err
+		}
+	}
+	if !mtime.IsZero() {
+		req.ModTimeUnixNano = mtime.UnixNano()
+	}
+	err := writeWithRetry(ctx, true, func() error {
+		_, err := client.WriteFileFromURL(ctx, req)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("unable to request credentials for a file upload: %w", err)
+		return fmt.Errorf("unable to write the file from URL: %w", err)
 	}
-	err = uploadToGCS(ctx, resp.GetFields(), r, dst, resp.GetUrl())
+	if opts.verifyMode {
+		if err := verifyFileMode(ctx, inst, dst, mode); err != nil {
+			return err
+		}
+	}
+	return opts.recordUpload(h.Sum(nil), int64(size), dst, inst)
+}
+
+// verifyFileMode checks that the file dst on inst has the permission bits
+// of mode.
+func verifyFileMode(ctx context.Context, inst, dst string, mode os.FileMode) error {
+	client := gomoteServerClient(ctx)
+	dir, name := path.Dir(dst), path.Base(dst)
+	resp, err := client.ListDirectory(ctx, &protos.ListDirectoryRequest{
+		GomoteId:  inst,
+		Directory: dir,
+	})
 	if err != nil {
-		return fmt.Errorf("unable to upload file to GCS: %w", err)
+		return fmt.Errorf("unable to list %q to verify the mode of %q: %w", dir, dst, err)
+	}
+	want := mode.Perm().String()
+	for _, entry := range resp.GetEntries() {
+		de := buildlet.DirEntry{Line: entry}
+		if de.Name() != name {
+			continue
+		}
+		if got := de.Perm(); got != want {
+			return fmt.Errorf("%s on %s has mode %s after writing; want %s", dst, inst, got, want)
+		}
+		return nil
 	}
-	_, err = client.WriteFileFromURL(ctx, &protos.WriteFileFromURLRequest{
-		GomoteId: inst,
-		Url:      fmt.Sprintf("%s%s", resp.GetUrl(), resp.GetObjectName()),
-		Filename: dst,
-		Mode:     uint32(mode),
+	return fmt.Errorf("%s on %s not found after writing", dst, inst)
+}
+
+// remoteModTime returns the modification time of the file dst on inst. It
+// reports false if dst isn't listed there, including if its directory
+// can't be listed, as when it doesn't exist yet.
+func remoteModTime(ctx context.Context, inst, dst string) (time.Time, bool) {
+	client := gomoteServerClient(ctx)
+	resp, err := client.ListDirectory(ctx, &protos.ListDirectoryRequest{
+		GomoteId:  inst,
+		Directory: path.Dir(dst),
 	})
 	if err != nil {
-		return fmt.Errorf("unable to write the file from URL: %w", err)
+		return time.Time{}, false
 	}
-	return nil
+	name := path.Base(dst)
+	for _, entry := range resp.GetEntries() {
+		if de := (buildlet.DirEntry{Line: entry}); de.Name() == name {
+			return de.ModTime()
+		}
+	}
+	return time.Time{}, false
+}
+
+// writeRetryBackoff is how long writeWithRetry waits before retrying a
+// write for the first time. The wait doubles for each later attempt.
+// writeRetryLog is where the retries are reported.
+var (
+	writeRetryBackoff           = time.Second
+	writeRetryLog     io.Writer = os.Stderr
+)
+
+// writeWithRetry calls write, which is expected to instruct an instance to
+// fetch an already uploaded object, until it succeeds or fails with an error
+// that is not worth retrying. The object has already been uploaded by the time
+// write is called, so retrying it is cheap.
+//
+// A write that times out may still have completed on the instance, so write
+// is only retried if idempotent is true, meaning that repeating a completed
+// write leaves the instance as it is.
+func writeWithRetry(ctx context.Context, idempotent bool, write func() error) error {
+	const maxAttempts = 4
+	backoff := writeRetryBackoff
+	for attempt := 1; ; attempt++ {
+		err := write()
+		if err == nil || attempt == maxAttempts || !isRetryableWriteError(err) {
+			return err
+		}
+		if !idempotent {
+			return fmt.Errorf("%w (not retried, since the write may have completed on the instance)", err)
+		}
+		fmt.Fprintf(writeRetryLog, "# write from URL failed (attempt %d of %d), retrying in %v: %v\n", attempt, maxAttempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// tarWriteIdempotent reports whether writing a tarball can be repeated
+// safely. A repeated write fails once the first one has filled the
+// directory when -require-clean-dir is set, and cleans away what was
+// extracted meanwhile by others when -clean is set.
+func (o *putOptions) tarWriteIdempotent() bool {
+	return !o.clean && !o.requireEmpty
+}
+
+// isRetryableWriteError reports whether err is a transient failure of one of
+// the WriteFromURL endpoints.
+func isRetryableWriteError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// upload uploads body as name using the credentials in resp. If
+// -concurrency-per-host is set, it first waits until fewer uploads than
+// that to the host of resp's URL are in flight.
+func (o *putOptions) upload(ctx context.Context, resp *protos.UploadFileResponse, body io.Reader, name string) error {
+	release, err := o.uploadHosts.acquire(ctx, resp.GetUrl())
+	if err != nil {
+		return err
+	}
+	defer release()
+	return uploadToGCS(ctx, resp.GetFields(), body, name, resp.GetUrl(), o.verboseHTTP)
 }
 
-func uploadToGCS(ctx context.Context, fields map[string]string, file io.Reader, filename, url string) error {
+// uploadToGCS uploads file as filename by posting it to url
+// along with the signed form fields.
+//
+// If verbose is true, the request and the response are logged to stderr, with
+// any credentials redacted.
+func uploadToGCS(ctx context.Context, fields map[string]string, file io.Reader, filename, url string, verbose bool) error {
 	buf := new(bytes.Buffer)
 	mw := multipart.NewWriter(buf)
 
@@ -405,12 +1820,68 @@ func uploadToGCS(ctx context.Context, fields map[string]string, file io.Reader,
 		return fmt.Errorf("unable to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", mw.FormDataContentType())
+	if verbose {
+		fmt.Fprintf(os.Stderr, "# POST %s\n", redactURL(url))
+		for _, k := range sortedKeys(fields) {
+			v := fields[k]
+			if isSecretField(k) {
+				v = "REDACTED"
+			}
+			fmt.Fprintf(os.Stderr, "#   field %s=%s\n", k, v)
+		}
+	}
 	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("http request failed: %w", err)
 	}
+	defer res.Body.Close()
+	if verbose {
+		// GCS explains most failures in an XML error document.
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+		fmt.Fprintf(os.Stderr, "# response: %s\n", res.Status)
+		if len(body) > 0 {
+			fmt.Fprintf(os.Stderr, "# response body: %s\n", body)
+		}
+	}
 	if res.StatusCode != http.StatusNoContent {
 		return fmt.Errorf("http post failed: status code=%d", res.StatusCode)
 	}
 	return nil
 }
+
+// redactURL returns rawURL with the values of any query parameters
+// carrying credentials replaced.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(unparsable URL)"
+	}
+	q := u.Query()
+	for k := range q {
+		if isSecretField(k) {
+			q.Set(k, "REDACTED")
+		}
+	}
+	u.RawQuery = q.Encode()
+	if u.User != nil {
+		u.User = url.User("REDACTED")
+	}
+	return u.String()
+}
+
+// isSecretField reports whether the upload form field or signed URL query
+// parameter named k carries credentials, as the signatures of Google Cloud
+// Storage, S3, and Azure ("sig") URLs do.
+func isSecretField(k string) bool {
+	k = strings.ToLower(k)
+	return k == "policy" || k == "sig" || strings.Contains(k, "signature") || strings.Contains(k, "credential") || strings.Contains(k, "token") || k == "googleaccessid"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/build/internal/gomote/protos"
+)
+
+// putStatus lists the files and tarballs recently put on instances.
+func putStatus(args []string) error {
+	fs := flag.NewFlagSet("put-status", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "put-status usage: gomote put-status [instance]")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Lists what was recently put on the instance, oldest first, as recorded by the server.")
+		fmt.Fprintln(os.Stderr, "Each line is the time, kind, destination, and source, followed by the build ID if the")
+		fmt.Fprintln(os.Stderr, "put was tagged with one (see put -build-id).")
+		fmt.Fprintln(os.Stderr, "The record is best-effort and covers only a limited number of recent puts.")
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, "Instance name is optional if a group is specified.")
+		fs.PrintDefaults()
+		os.Exit(1)
+	}
+	fs.Parse(args)
+
+	var statusSet []string
+	switch fs.NArg() {
+	case 0:
+		if activeGroup == nil {
+			fmt.Fprintln(os.Stderr, "no active group found; need an active group with no arguments")
+			fs.Usage()
+		}
+		for _, inst := range activeGroup.Instances {
+			statusSet = append(statusSet, inst)
+		}
+	case 1:
+		statusSet = []string{fs.Arg(0)}
+	default:
+		fmt.Fprintln(os.Stderr, "error: too many arguments")
+		fs.Usage()
+	}
+
+	ctx := context.Background()
+	client := gomoteServerClient(ctx)
+	for _, inst := range statusSet {
+		resp, err := client.ListPuts(ctx, &protos.ListPutsRequest{
+			GomoteId: inst,
+		})
+		if err != nil {
+			return fmt.Errorf("unable to list puts to %q: %w", inst, err)
+		}
+		if len(statusSet) > 1 {
+			fmt.Fprintf(os.Stdout, "# %s\n", inst)
+		}
+		for _, p := range resp.GetPuts() {
+			dst := p.GetDestination()
+			if dst == "" {
+				dst = "."
+			}
+			line := fmt.Sprintf("%s\t%s\t%s\t%s", time.Unix(p.GetTime(), 0).Format(time.RFC3339), strings.ToLower(p.GetKind().String()), dst, p.GetSource())
+			if id := p.GetBuildId(); id != "" {
+				line += "\t" + id
+			}
+			fmt.Fprintln(os.Stdout, line)
+		}
+		if len(statusSet) > 1 {
+			fmt.Fprintln(os.Stdout)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"sync"
+)
+
+// putReport is the report written by the put commands' -report-json flag.
+// Fields may be added to it in the future, but existing fields won't be
+// renamed, removed, or change meaning.
+type putReport struct {
+	// Results has the result of each write to an instance, sorted by
+	// instance name. There's one per source for each instance if there
+	// are several, as with -source-list.
+	Results []*putResult `json:"results"`
+}
+
+// putResult is the result of writing a source to one instance.
+type putResult struct {
+	Instance    string `json:"instance"`
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+
+	// Bytes, SHA256, and Checksum describe what was uploaded from this
+	// machine. They're empty if the instance fetched the source itself,
+	// as it does for a URL or a git hash. Checksum is set if
+	// -checksum-algo was given, as "<algo>:<hex>"; SHA256 is set unless
+	// it picked another algorithm.
+	Bytes    int64  `json:"bytes,omitempty"`
+	SHA256   string `json:"sha256,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+
+	// DurationSeconds is how long the write took, including the upload.
+	DurationSeconds float64 `json:"duration_seconds"`
+
+	// Success is whether the write succeeded. If not, Error describes
+	// why. A write skipped because of -resume-token is a success.
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// putReporter collects the results for a -report-json report.
+type putReporter struct {
+	path string // or "-" for stdout
+
+	mu      sync.Mutex
+	uploads map[uploadKey]upload
+	results []*putResult
+}
+
+// uploadKey identifies a write: the instance and the destination on it.
+// The destination is cleaned, so that "" and "." are the same directory.
+type uploadKey struct {
+	inst, dst string
+}
+
+func newUploadKey(inst, dst string) uploadKey {
+	return uploadKey{inst: inst, dst: path.Clean(dst)}
+}
+
+// upload describes the bytes uploaded for a write to an instance.
+type upload struct {
+	size int64
+	algo *checksumAlgo // or nil for the default of SHA-256
+	sum  []byte
+}
+
+func newPutReporter(path string) *putReporter {
+	return &putReporter{path: path, uploads: make(map[uploadKey]upload)}
+}
+
+// recordUpload records that size bytes with the digest sum, computed with
+// algo, were uploaded for the write to dst on inst.
+func (r *putReporter) recordUpload(inst, dst string, size int64, algo *checksumAlgo, sum []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.uploads[newUploadKey(inst, dst)] = upload{size: size, algo: algo, sum: sum}
+}
+
+// add adds the result of a write to the report, filling in what was
+// uploaded for it. What was uploaded is forgotten, so that it's never
+// attached to the result of a later write to the same place, such as one
+// skipped because of -resume-token.
+func (r *putReporter) add(res *putResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := newUploadKey(res.Instance, res.Destination)
+	if u, ok := r.uploads[key]; ok {
+		delete(r.uploads, key)
+		res.Bytes = u.size
+		if u.algo == nil || u.algo.name == "sha256" {
+			res.SHA256 = fmt.Sprintf("%x", u.sum)
+		}
+		if u.algo != nil {
+			res.Checksum = fmt.Sprintf("%s:%x", u.algo.name, u.sum)
+		}
+	}
+	r.results = append(r.results, res)
+}
+
+// write writes the report to its path.
+func (r *putReporter) write() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	results := append([]*putResult(nil), r.results...)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Instance < results[j].Instance })
+	b, err := json.MarshalIndent(&putReport{Results: results}, "", "\t")
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+	if r.path == "-" {
+		_, err = os.Stdout.Write(b)
+	} else {
+		err = os.WriteFile(r.path, b, 0644)
+	}
+	if err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	return nil
+}
+
+// byteCounter is an io.Writer which counts the bytes written to it.
+type byteCounter int64
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	*c += byteCounter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import "testing"
+
+func TestPutReporterUploads(t *testing.T) {
+	r := newPutReporter("-")
+	r.recordUpload("a", "", 3, nil, []byte{0xab})
+	r.recordUpload("b", "go", 5, nil, []byte{0xcd})
+
+	testCases := []struct {
+		inst, dst string
+		wantBytes int64
+		wantSum   string
+	}{
+		{"a", ".", 3, "ab"},
+		// What was uploaded for a write isn't reused for a later one.
+		{"a", ".", 0, ""},
+		{"b", "go/src", 0, ""},
+		{"b", "go", 5, "cd"},
+		{"c", "go", 0, ""},
+	}
+	for _, tc := range testCases {
+		res := &putResult{Instance: tc.inst, Destination: tc.dst}
+		r.add(res)
+		if res.Bytes != tc.wantBytes || res.SHA256 != tc.wantSum {
+			t.Errorf("add(%s, %q) set Bytes, SHA256 = %d, %q; want %d, %q", tc.inst, tc.dst, res.Bytes, res.SHA256, tc.wantBytes, tc.wantSum)
+		}
+	}
+}
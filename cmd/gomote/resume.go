@@ -0,0 +1,110 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// resumeToken is a local record of the writes to instances that completed,
+// so that rerunning an interrupted put can skip them. Each line is of the
+// form "<source digest>\t<source>\t<destination>\t<instance>".
+type resumeToken struct {
+	path string
+
+	mu   sync.Mutex // guards done and writes to f
+	done map[string]bool
+	f    *os.File
+}
+
+// openResumeToken opens the resume token at path, reading the writes
+// recorded by a previous run if it exists.
+func openResumeToken(path string) (*resumeToken, error) {
+	t := &resumeToken{path: path, done: make(map[string]bool)}
+	if f, err := os.Open(path); err == nil {
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			t.done[sc.Text()] = true
+		}
+		f.Close()
+		if err := sc.Err(); err != nil {
+			return nil, fmt.Errorf("reading resume token: %w", err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("opening resume token: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening resume token: %w", err)
+	}
+	t.f = f
+	return t, nil
+}
+
+func resumeKey(digest, src, dst, inst string) string {
+	return strings.Join([]string{digest, src, dst, inst}, "\t")
+}
+
+// completed reports whether the write of src with the given digest to
+// dst on inst completed in a previous run.
+func (t *resumeToken) completed(digest, src, dst, inst string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.done[resumeKey(digest, src, dst, inst)]
+}
+
+// record records that the write of src with the given digest to dst on
+// inst completed.
+func (t *resumeToken) record(digest, src, dst, inst string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := resumeKey(digest, src, dst, inst)
+	t.done[key] = true
+	if _, err := fmt.Fprintln(t.f, key); err != nil {
+		return fmt.Errorf("writing resume token: %w", err)
+	}
+	// Make sure the record survives the interruption it exists for.
+	if err := t.f.Sync(); err != nil {
+		return fmt.Errorf("writing resume token: %w", err)
+	}
+	return nil
+}
+
+// remove closes and deletes the token. It's called once every write has
+// completed, since there is nothing left to resume.
+func (t *resumeToken) remove() error {
+	t.Close()
+	return os.Remove(t.path)
+}
+
+func (t *resumeToken) Close() error {
+	return t.f.Close()
+}
+
+// bytesDigest returns the hex-encoded SHA-256 digest of b.
+func bytesDigest(b []byte) string {
+	return fmt.Sprintf("%x", sha256.Sum256(b))
+}
+
+// fileDigest returns the hex-encoded SHA-256 digest of the contents of the
+// local file at path.
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("hashing %q: %w", path, err)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// sourceListEntry is a source listed in a puttar -source-list file.
+type sourceListEntry struct {
+	pos string // file:line, for errors
+	src string
+	dir string
+}
+
+// readSourceList reads the -source-list file at path, or stdin if path is
+// "-". Each line is a source optionally followed by the directory to
+// extract it into, which defaults to dir. Blank lines and lines starting
+// with # are ignored.
+func readSourceList(path, dir string) ([]sourceListEntry, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	entries, err := parseSourceList(path, r, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if path == "-" && e.src == "-" {
+			return nil, fmt.Errorf("%s: source list read from stdin can't use stdin as a source", e.pos)
+		}
+	}
+	return entries, nil
+}
+
+func parseSourceList(name string, r io.Reader, dir string) ([]sourceListEntry, error) {
+	var entries []sourceListEntry
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		e := sourceListEntry{pos: fmt.Sprintf("%s:%d", name, line), dir: dir}
+		switch f := strings.Fields(text); len(f) {
+		case 1:
+			e.src = f[0]
+		case 2:
+			e.src, e.dir = f[0], f[1]
+		default:
+			return nil, fmt.Errorf("%s: want \"<source> [<dir>]\", got %q", e.pos, text)
+		}
+		entries = append(entries, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", name, err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%s: no sources listed", name)
+	}
+	return entries, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"golang.org/x/build/internal/gomote/protos"
+)
+
+// parseByteSize parses a positive size in bytes, such as "1048576", "512K",
+// "64M", or "2G", where the suffixes are binary multiples.
+func parseByteSize(s string) (int64, error) {
+	mult := int64(1)
+	num := s
+	switch {
+	case strings.HasSuffix(s, "K"):
+		mult, num = 1<<10, strings.TrimSuffix(s, "K")
+	case strings.HasSuffix(s, "M"):
+		mult, num = 1<<20, strings.TrimSuffix(s, "M")
+	case strings.HasSuffix(s, "G"):
+		mult, num = 1<<30, strings.TrimSuffix(s, "G")
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n <= 0 || n > (1<<62)/mult {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// uploadParts uploads the contents of r as a sequence of objects of at most
+// o.splitLarge bytes each, and returns their URLs in order. The name is
+// only used to label the uploads.
+func (o *putOptions) uploadParts(ctx context.Context, client protos.GomoteServiceClient, r io.Reader, name string) ([]string, error) {
+	br := bufio.NewReader(r)
+	var urls []string
+	for {
+		resp, err := client.UploadFile(ctx, o.uploadFileRequest())
+		if err != nil {
+			return nil, fmt.Errorf("unable to request credentials for a file upload: %w", err)
+		}
+		if err := o.checkUploadResponse(resp); err != nil {
+			return nil, err
+		}
+		part := fmt.Sprintf("%s.part%d", name, len(urls))
+		if err := o.upload(ctx, resp, io.LimitReader(br, o.splitLarge), part); err != nil {
+			return nil, fmt.Errorf("unable to upload part %d to GCS: %w", len(urls), err)
+		}
+		url := o.objectURL(resp)
+		if err := o.mirror(ctx, client, url); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+		if _, err := br.Peek(1); err == io.EOF {
+			return urls, nil
+		} else if err != nil {
+			return nil, err
+		}
+	}
+}
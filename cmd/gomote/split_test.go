@@ -0,0 +1,133 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/build/internal/gomote/protos"
+	"google.golang.org/grpc"
+)
+
+func TestParseByteSize(t *testing.T) {
+	testCases := []struct {
+		in      string
+		want    int64
+		wantErr bool
+	}{
+		{in: "1", want: 1},
+		{in: "1048576", want: 1 << 20},
+		{in: "512K", want: 512 << 10},
+		{in: "64M", want: 64 << 20},
+		{in: "2G", want: 2 << 30},
+		{in: "4294967296G", want: 1 << 62},
+		{in: "4294967297G", wantErr: true},
+		{in: "9223372036854775807", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+		{in: "0", wantErr: true},
+		{in: "0M", wantErr: true},
+		{in: "-1", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "M", wantErr: true},
+		{in: "1.5G", wantErr: true},
+		{in: "2g", wantErr: true},
+		{in: "2GB", wantErr: true},
+	}
+	for _, tc := range testCases {
+		got, err := parseByteSize(tc.in)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("parseByteSize(%q) = %d, nil; want error", tc.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tc.want {
+			t.Errorf("parseByteSize(%q) = %d, %v; want %d, nil", tc.in, got, err, tc.want)
+		}
+	}
+}
+
+// fakeUploadClient hands out credentials for uploads to a test server.
+type fakeUploadClient struct {
+	protos.GomoteServiceClient
+	url string
+
+	mu sync.Mutex
+	n  int
+}
+
+func (c *fakeUploadClient) UploadFile(ctx context.Context, in *protos.UploadFileRequest, opts ...grpc.CallOption) (*protos.UploadFileResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.n++
+	name := fmt.Sprintf("obj%d", c.n)
+	return &protos.UploadFileResponse{Url: c.url + "/", ObjectName: name, Fields: map[string]string{"key": name}}, nil
+}
+
+func TestUploadParts(t *testing.T) {
+	var mu sync.Mutex
+	uploaded := make(map[string]string) // object name to contents
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, err := io.ReadAll(f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		uploaded[r.FormValue("key")] = string(data)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	const size = 4
+	testCases := []struct {
+		desc string
+		data string
+		want []string
+	}{
+		{"empty", "", []string{""}},
+		{"smaller than a part", "ab", []string{"ab"}},
+		{"one part", "abcd", []string{"abcd"}},
+		{"exact multiple", "abcdefgh", []string{"abcd", "efgh"}},
+		{"remainder", "abcdefghi", []string{"abcd", "efgh", "i"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			mu.Lock()
+			uploaded = make(map[string]string)
+			mu.Unlock()
+			client := &fakeUploadClient{url: srv.URL}
+			o := &putOptions{splitLarge: size}
+			urls, err := o.uploadParts(context.Background(), client, strings.NewReader(tc.data), "f")
+			if err != nil {
+				t.Fatalf("uploadParts() = %v", err)
+			}
+			var got []string
+			for _, u := range urls {
+				got = append(got, uploaded[strings.TrimPrefix(u, srv.URL+"/")])
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("uploadParts() uploaded %q; want %q", got, tc.want)
+			}
+			if len(uploaded) != len(urls) {
+				t.Errorf("uploadParts() uploaded %d objects but returned %d URLs", len(uploaded), len(urls))
+			}
+		})
+	}
+}
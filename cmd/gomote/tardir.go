@@ -0,0 +1,213 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/build/tarutil"
+)
+
+// tarGzDir returns a .tar.gz of the directory tree rooted at root.
+// Entry names are relative to root and forward slash separated.
+// Only directories and regular files are included. Files which are
+// already compressed are stored without being compressed again.
+// Headers are written in the given format (see setTarFormat).
+// Paths skipped by filter, which may be nil, are left out. If eol isn't
+// nil, it normalizes the line endings of text files.
+func tarGzDir(root string, format tar.Format, filter *dirFilter, eol *eolConverter) (*bytes.Buffer, error) {
+	var fl tarutil.FileList
+	skip := filter.walker()
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if skipped, err := skip(path, rel, d.IsDir()); err != nil {
+			return err
+		} else if skipped {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if rel == "." {
+			return nil
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(fi, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		setTarFormat(header, format)
+		if d.IsDir() {
+			header.Name += "/"
+			fl.AddHeader(header)
+			return nil
+		}
+		content := &lazyFile{path: path, size: header.Size}
+		compressed, err := isCompressedFile(path)
+		if err != nil {
+			return err
+		}
+		if eol != nil && !compressed {
+			data, ok, err := eol.textContent(path)
+			if err != nil {
+				return err
+			}
+			if ok {
+				header.Size = int64(len(data))
+				fl.AddRegular(header, header.Size, bytes.NewReader(data))
+				return nil
+			}
+		}
+		if compressed {
+			fl.AddRegularStored(header, header.Size, content)
+		} else {
+			fl.AddRegular(header, header.Size, content)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("packaging %q: %w", root, err)
+	}
+	var buf bytes.Buffer
+	tgz := fl.TarGz()
+	defer tgz.Close()
+	if _, err := io.Copy(&buf, tgz); err != nil {
+		return nil, fmt.Errorf("packaging %q: %w", root, err)
+	}
+	return &buf, nil
+}
+
+// parseTarFormat parses the value of the -tar-format flag.
+func parseTarFormat(s string) (tar.Format, error) {
+	switch s {
+	case "":
+		return tar.FormatUnknown, nil
+	case "gnu":
+		return tar.FormatGNU, nil
+	case "pax":
+		return tar.FormatPAX, nil
+	case "ustar":
+		return tar.FormatUSTAR, nil
+	}
+	return tar.FormatUnknown, fmt.Errorf("unknown tar format %q; want gnu, pax, or ustar", s)
+}
+
+// setTarFormat forces h to be written in format. If format is
+// tar.FormatUnknown, h is left alone and archive/tar picks a format
+// which can encode it. Metadata which format can't hold at all, like
+// PAX records in anything but PAX, is dropped; anything else it can't
+// encode, like a long name in USTAR, makes writing h fail.
+func setTarFormat(h *tar.Header, format tar.Format) {
+	if format == tar.FormatUnknown {
+		return
+	}
+	h.Format = format
+	if format != tar.FormatPAX {
+		h.PAXRecords = nil
+		h.Xattrs = nil // deprecated, but still set by tar.Reader
+	}
+	if format == tar.FormatUSTAR {
+		h.AccessTime = time.Time{}
+		h.ChangeTime = time.Time{}
+	}
+}
+
+// tarWriter is a tar.Writer which forces the headers it writes into a
+// format, as set by setTarFormat.
+type tarWriter struct {
+	*tar.Writer
+	format tar.Format
+}
+
+func newTarWriter(w io.Writer, format tar.Format) *tarWriter {
+	return &tarWriter{Writer: tar.NewWriter(w), format: format}
+}
+
+func (tw *tarWriter) WriteHeader(h *tar.Header) error {
+	setTarFormat(h, tw.format)
+	return tw.Writer.WriteHeader(h)
+}
+
+// compressedMagic are the leading bytes of common compressed formats.
+var compressedMagic = [][]byte{
+	{0x1f, 0x8b},                  // gzip
+	[]byte("BZh"),                 // bzip2
+	{0xfd, '7', 'z', 'X', 'Z', 0}, // xz
+	{0x28, 0xb5, 0x2f, 0xfd},      // zstd
+	[]byte("PK\x03\x04"),          // zip
+	{'7', 'z', 0xbc, 0xaf},        // 7z
+	{0x89, 'P', 'N', 'G'},         // PNG
+	{0xff, 0xd8, 0xff},            // JPEG
+}
+
+// isCompressedFile reports whether the file at path starts like a
+// compressed file.
+func isCompressedFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	head := make([]byte, 6)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, err
+	}
+	for _, magic := range compressedMagic {
+		if bytes.HasPrefix(head[:n], magic) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// lazyFile is an io.ReaderAt for a file which is only open while it's
+// being read, so that a tarutil.FileList of a large tree doesn't hold
+// a descriptor for every file in it. It expects to be read in order,
+// and closes the file once the last byte has been read.
+type lazyFile struct {
+	path string
+	size int64
+	f    *os.File
+}
+
+func (lf *lazyFile) ReadAt(p []byte, off int64) (int, error) {
+	if lf.f == nil {
+		f, err := os.Open(lf.path)
+		if err != nil {
+			return 0, err
+		}
+		lf.f = f
+	}
+	n, err := lf.f.ReadAt(p, off)
+	if off+int64(n) >= lf.size || (err != nil && err != io.EOF) {
+		lf.f.Close()
+		lf.f = nil
+	}
+	if err != nil && err != io.EOF {
+		return n, fmt.Errorf("error copying contents of %s: %w", lf.path, err)
+	}
+	return n, err
+}
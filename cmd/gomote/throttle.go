@@ -0,0 +1,54 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"io"
+
+	"golang.org/x/time/rate"
+)
+
+// maxThrottleBurst caps the number of bytes an upload throttled by
+// -throttle-per-instance may send at once.
+const maxThrottleBurst = 1 << 20
+
+// throttle returns r, limited to -throttle-per-instance bytes per second
+// if it's set. Each call has its own limit, so that each instance's
+// uploads are limited independently of how many run at once.
+func (o *putOptions) throttle(ctx context.Context, r io.Reader) io.Reader {
+	if o.throttlePerInstance <= 0 {
+		return r
+	}
+	burst := maxThrottleBurst
+	if o.throttlePerInstance < maxThrottleBurst {
+		burst = int(o.throttlePerInstance)
+	}
+	return &throttledReader{
+		ctx: ctx,
+		r:   r,
+		lim: rate.NewLimiter(rate.Limit(o.throttlePerInstance), burst),
+	}
+}
+
+// throttledReader limits the rate at which bytes are read from r.
+type throttledReader struct {
+	ctx context.Context
+	r   io.Reader
+	lim *rate.Limiter
+}
+
+func (tr *throttledReader) Read(b []byte) (int, error) {
+	if len(b) > tr.lim.Burst() {
+		b = b[:tr.lim.Burst()]
+	}
+	n, err := tr.r.Read(b)
+	if n > 0 {
+		if werr := tr.lim.WaitN(tr.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// transformFile pipes the contents of the file named name read from r
+// through the local command given by the -transform flag, for a put to inst,
+// and returns the command's output.
+//
+// The command is split into fields on white space and run without a shell.
+// It's run with $GOMOTE_INSTANCE set to inst and $GOMOTE_FILE set to name.
+func (o *putOptions) transformFile(ctx context.Context, inst, name string, r io.Reader) ([]byte, error) {
+	args := strings.Fields(o.transform)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("empty -transform command")
+	}
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(), "GOMOTE_INSTANCE="+inst, "GOMOTE_FILE="+name)
+	cmd.Stdin = r
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if stderr.Len() > 0 {
+			err = fmt.Errorf("%w\n%s", err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return nil, fmt.Errorf("transforming %s for %s: %w", name, inst, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// transformTarGz returns a copy of the .tar.gz read from r in which
+// the contents of each regular file have been piped through the
+// -transform command for a put to inst. Headers are written in the
+// format set by -tar-format.
+func (o *putOptions) transformTarGz(ctx context.Context, inst string, r io.Reader) (*bytes.Buffer, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(zr)
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := newTarWriter(zw, o.tarFormat)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		var data []byte
+		if h.Typeflag == tar.TypeReg {
+			data, err = o.transformFile(ctx, inst, h.Name, tr)
+			if err != nil {
+				return nil, err
+			}
+			h.Size = int64(len(data))
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// zipMagic are the leading bytes of a zip file, and of an empty one.
+var zipMagic = [][]byte{[]byte("PK\x03\x04"), []byte("PK\x05\x06")}
+
+// isZip reports whether head, the start of a file, looks like a zip file.
+func isZip(head []byte) bool {
+	for _, magic := range zipMagic {
+		if bytes.HasPrefix(head, magic) {
+			return true
+		}
+	}
+	return false
+}
+
+// isZipFile reports whether the local file at path looks like a zip file.
+func isZipFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	head := make([]byte, 4)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return false, fmt.Errorf("reading %q: %w", path, err)
+	}
+	return isZip(head[:n]), nil
+}
+
+// fetchZip downloads the zip file at url, so that it can be converted
+// to a .tar.gz locally, since instances only extract tarballs.
+func fetchZip(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: %s", url, res.Status)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// zipTarGz returns a .tar.gz with the directories and regular files in the
+// zip file data, keeping their modes and modification times. Since the
+// buildlet skips symlinks when it extracts a tarball, a zip file with
+// symlinks is an error. Entry names are cleaned like package payloads, so
+// they can't escape the destination. Headers are written in tarFormat (see
+// setTarFormat).
+func zipTarGz(data []byte, tarFormat tar.Format) (*bytes.Buffer, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := newTarWriter(zw, tarFormat)
+	for _, f := range zr.File {
+		name := payloadName(f.Name)
+		if name == "" {
+			continue
+		}
+		mode := f.Mode()
+		h := &tar.Header{
+			Name:    name,
+			Mode:    int64(mode.Perm()),
+			ModTime: f.Modified,
+		}
+		var content []byte
+		switch {
+		case mode.IsDir():
+			h.Typeflag = tar.TypeDir
+			h.Name += "/"
+			if mode.Perm()&0100 == 0 {
+				// Zip files without Unix modes, like those made on
+				// Windows, don't give directories search permission.
+				h.Mode = 0755
+			}
+		case mode&os.ModeSymlink != 0:
+			return nil, fmt.Errorf("%s: symlinks aren't supported, since instances don't extract them", f.Name)
+		case mode.IsRegular():
+			if content, err = readZipFile(f); err != nil {
+				return nil, err
+			}
+			h.Typeflag = tar.TypeReg
+			h.Size = int64(len(content))
+		default:
+			return nil, fmt.Errorf("%s: unsupported file type %v", f.Name, mode.Type())
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return data, nil
+}
@@ -8,16 +8,25 @@
 package gomote
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"log"
 	"net/http"
+	"os"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
+	"unicode"
 
 	"cloud.google.com/go/storage"
 	"github.com/google/uuid"
@@ -30,6 +39,7 @@ import (
 	"golang.org/x/build/internal/coordinator/schedule"
 	"golang.org/x/build/internal/envutil"
 	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/build/internal/gomote/sealed"
 	"golang.org/x/build/types"
 	"golang.org/x/crypto/ssh"
 	"google.golang.org/grpc/codes"
@@ -59,20 +69,40 @@ type Server struct {
 	gceBucketName           string
 	scheduler               scheduler
 	sshCertificateAuthority ssh.Signer
+
+	// uploadBuckets contains the GCS buckets, other than the default transfer
+	// bucket, which callers may request uploads to. It is keyed by bucket name.
+	uploadBuckets map[string]bucketHandle
+
+	// puts records the recent writes to each instance.
+	puts putLog
+
+	// uploads records who each recently uploaded object is for.
+	uploads uploadOwners
+
+	// keys holds the decryption keys set for each instance.
+	keys decryptionKeys
 }
 
 // New creates a gomote server. If the rawCAPriKey is invalid, the program will exit.
-func New(rsp *remote.SessionPool, sched *schedule.Scheduler, rawCAPriKey []byte, gomoteGCSBucket string, storageClient *storage.Client) *Server {
+// The uploadBuckets, which are GCS buckets, are made available as alternatives to gomoteGCSBucket
+// for file uploads.
+func New(rsp *remote.SessionPool, sched *schedule.Scheduler, rawCAPriKey []byte, gomoteGCSBucket string, storageClient *storage.Client, uploadBuckets ...string) *Server {
 	signer, err := ssh.ParsePrivateKey(rawCAPriKey)
 	if err != nil {
 		log.Fatalf("unable to parse raw certificate authority private key into signer=%s", err)
 	}
+	ub := make(map[string]bucketHandle)
+	for _, name := range uploadBuckets {
+		ub[name] = storageClient.Bucket(name)
+	}
 	return &Server{
 		bucket:                  storageClient.Bucket(gomoteGCSBucket),
 		buildlets:               rsp,
 		gceBucketName:           gomoteGCSBucket,
 		scheduler:               sched,
 		sshCertificateAuthority: signer,
+		uploadBuckets:           ub,
 	}
 }
 
@@ -172,6 +202,10 @@ func (s *Server) CreateInstance(req *protos.CreateInstanceRequest, stream protos
 				return status.Errorf(codes.Unknown, "gomote creation failed: %s", r.err)
 			}
 			gomoteID := s.buildlets.AddSession(creds.ID, userName, req.GetBuilderType(), bconf.HostType, r.buildletClient)
+			// Sessions that expire are removed from the pool without a call to
+			// DestroyInstance, and their names are reused.
+			s.puts.forget(gomoteID)
+			s.keys.forget(gomoteID)
 			log.Printf("created buildlet %v for %v (%s)", gomoteID, userName, r.buildletClient.String())
 			session, err := s.buildlets.Session(gomoteID)
 			if err != nil {
@@ -221,6 +255,89 @@ func (s *Server) InstanceAlive(ctx context.Context, req *protos.InstanceAliveReq
 	return &protos.InstanceAliveResponse{}, nil
 }
 
+// InstanceHealth reports the load and available memory of a gomote instance, as read from
+// /proc on the instance. Only Linux instances are supported.
+func (s *Server) InstanceHealth(ctx context.Context, req *protos.InstanceHealthRequest) (*protos.InstanceHealthResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if req.GetGomoteId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid gomote ID")
+	}
+	ses, bc, err := s.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
+	if err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	if conf, ok := dashboard.Builders[ses.BuilderType]; !ok || conf.GOOS() != "linux" {
+		return nil, status.Errorf(codes.Unimplemented, "health is only available for Linux instances")
+	}
+	var out bytes.Buffer
+	remoteErr, execErr := bc.Exec(ctx, "/bin/sh", buildlet.ExecOpts{
+		Args:        []string{"-c", "cat /proc/loadavg /proc/meminfo && nproc"},
+		SystemLevel: true,
+		Output:      &out,
+	})
+	if execErr != nil {
+		return nil, status.Errorf(codes.Aborted, "unable to read instance health: %s", execErr)
+	}
+	if remoteErr != nil {
+		return nil, status.Errorf(codes.Unknown, "reading instance health failed: %s", remoteErr)
+	}
+	resp, err := parseInstanceHealth(out.String())
+	if err != nil {
+		log.Printf("InstanceHealth: unable to parse health of %q: %s", req.GetGomoteId(), err)
+		return nil, status.Errorf(codes.Internal, "unable to parse instance health")
+	}
+	return resp, nil
+}
+
+// parseInstanceHealth parses the contents of /proc/loadavg and /proc/meminfo
+// followed by the output of nproc.
+func parseInstanceHealth(out string) (*protos.InstanceHealthResponse, error) {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) < 3 {
+		return nil, errors.New("output too short")
+	}
+	resp := new(protos.InstanceHealthResponse)
+	f := strings.Fields(lines[0])
+	if len(f) == 0 {
+		return nil, errors.New("missing load average")
+	}
+	load, err := strconv.ParseFloat(f[0], 64)
+	if err != nil {
+		return nil, fmt.Errorf("bad load average: %w", err)
+	}
+	resp.LoadAverage = load
+	n, err := strconv.ParseInt(strings.TrimSpace(lines[len(lines)-1]), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("bad CPU count: %w", err)
+	}
+	resp.NumCpu = int32(n)
+	for _, line := range lines[1 : len(lines)-1] {
+		// Lines are like "MemAvailable:   12345678 kB".
+		f := strings.Fields(line)
+		if len(f) != 3 || f[2] != "kB" {
+			continue
+		}
+		kb, err := strconv.ParseInt(f[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s: %w", f[0], err)
+		}
+		switch f[0] {
+		case "MemTotal:":
+			resp.MemTotalBytes = kb << 10
+		case "MemAvailable:":
+			resp.MemAvailableBytes = kb << 10
+		}
+	}
+	if resp.MemTotalBytes == 0 || resp.MemAvailableBytes == 0 {
+		return nil, errors.New("missing memory information")
+	}
+	return resp, nil
+}
+
 // ListDirectory lists the contents of the directory on a gomote instance.
 func (s *Server) ListDirectory(ctx context.Context, req *protos.ListDirectoryRequest) (*protos.ListDirectoryResponse, error) {
 	creds, err := access.IAPFromContext(ctx)
@@ -273,6 +390,67 @@ func (s *Server) ListInstances(ctx context.Context, req *protos.ListInstancesReq
 	return res, nil
 }
 
+// ListPuts lists the files and tarballs most recently written to a gomote instance.
+func (s *Server) ListPuts(ctx context.Context, req *protos.ListPutsRequest) (*protos.ListPutsResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		log.Printf("ListPuts access.IAPFromContext(ctx) = nil, %s", err)
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if req.GetGomoteId() == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid gomote ID")
+	}
+	if _, err := s.session(req.GetGomoteId(), creds.ID); err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	return &protos.ListPutsResponse{
+		Puts: s.puts.list(req.GetGomoteId()),
+	}, nil
+}
+
+// MirrorObject copies an uploaded object to another of the buckets available for uploads,
+// such as one holding backups. The copy is made within GCS. Only the caller who requested
+// the credentials for uploading the object may mirror it.
+func (s *Server) MirrorObject(ctx context.Context, req *protos.MirrorObjectRequest) (*protos.MirrorObjectResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	srcName, src, ok := s.objectStoreBucket(req.GetUrl())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "URL is not for an uploaded object")
+	}
+	object, err := objectFromURL(srcName, req.GetUrl())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid object URL: %s", err)
+	}
+	dstName := req.GetDestinationBucket()
+	if dstName == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "missing destination bucket")
+	}
+	if dstName == srcName {
+		return nil, status.Errorf(codes.InvalidArgument, "object is already in bucket %q", dstName)
+	}
+	dst, ok := s.uploadBucket(dstName)
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "bucket %q is not available for uploads", dstName)
+	}
+	if !s.uploads.owns(creds.ID, srcName, object) {
+		return nil, status.Errorf(codes.PermissionDenied, "object was not uploaded by the caller")
+	}
+	if _, err := dst.Object(object).CopierFrom(src.Object(object)).Run(ctx); err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return nil, status.Errorf(codes.NotFound, "uploaded object not found")
+		}
+		log.Printf("MirrorObject: unable to copy %q to bucket %q: %s", object, dstName, err)
+		return nil, status.Errorf(codes.Internal, "unable to copy object")
+	}
+	return &protos.MirrorObjectResponse{
+		Url: fmt.Sprintf("https://storage.googleapis.com/%s/%s", dstName, object),
+	}, nil
+}
+
 // DestroyInstance will destroy a gomote instance. It will ensure that the caller is authenticated and is the owner of the instance
 // before it destroys the instance.
 func (s *Server) DestroyInstance(ctx context.Context, req *protos.DestroyInstanceRequest) (*protos.DestroyInstanceResponse, error) {
@@ -293,6 +471,8 @@ func (s *Server) DestroyInstance(ctx context.Context, req *protos.DestroyInstanc
 		log.Printf("DestroyInstance remote.DestroySession(%s) = %s", req.GetGomoteId(), err)
 		return nil, status.Errorf(codes.Internal, "unable to destroy gomote instance")
 	}
+	s.puts.forget(req.GetGomoteId())
+	s.keys.forget(req.GetGomoteId())
 	return &protos.DestroyInstanceResponse{}, nil
 }
 
@@ -385,7 +565,7 @@ func (s *Server) ReadTGZToURL(ctx context.Context, req *protos.ReadTGZToURLReque
 	if err := tgzWriter.Close(); err != nil {
 		return nil, status.Errorf(codes.Aborted, "unable to store object: %s", err)
 	}
-	url, err := s.signURLForDownload(objectName)
+	url, err := signURLForDownload(s.bucket, objectName)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "unable to create signed URL for download: %s", err)
 	}
@@ -417,6 +597,24 @@ func (s *Server) RemoveFiles(ctx context.Context, req *protos.RemoveFilesRequest
 	return &protos.RemoveFilesResponse{}, nil
 }
 
+// SetDecryptionKey sets the key which the uploads written to the gomote instance with decrypt set were sealed with.
+// The key is kept until the instance is destroyed, and never logged.
+func (s *Server) SetDecryptionKey(ctx context.Context, req *protos.SetDecryptionKeyRequest) (*protos.SetDecryptionKeyResponse, error) {
+	creds, err := access.IAPFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
+	}
+	if len(req.GetKey()) != sealed.KeySize {
+		return nil, status.Errorf(codes.InvalidArgument, "key must be %d bytes long", sealed.KeySize)
+	}
+	if _, err := s.session(req.GetGomoteId(), creds.ID); err != nil {
+		// the helper function returns meaningful GRPC error.
+		return nil, err
+	}
+	s.keys.set(req.GetGomoteId(), req.GetKey())
+	return &protos.SetDecryptionKeyResponse{}, nil
+}
+
 // SignSSHKey signs the public SSH key with a certificate. The signed public SSH key is intended for use with the gomote service SSH
 // server. It will be signed by the certificate authority of the server and will restrict access to the gomote instance that it was
 // signed for.
@@ -442,16 +640,42 @@ func (s *Server) SignSSHKey(ctx context.Context, req *protos.SignSSHKeyRequest)
 // UploadFile creates a URL and a set of HTTP post fields which are used to upload a file to a staging GCS bucket. Uploaded files are made available to the
 // gomote instances via a subsequent call to one of the WriteFromURL endpoints.
 func (s *Server) UploadFile(ctx context.Context, req *protos.UploadFileRequest) (*protos.UploadFileResponse, error) {
-	_, err := access.IAPFromContext(ctx)
+	creds, err := access.IAPFromContext(ctx)
 	if err != nil {
 		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
 	}
-	objectName := uuid.NewString()
-	url, fields, err := s.signURLForUpload(objectName)
+	bucket, ok := s.uploadBucket(req.GetBucket())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "bucket %q is not available for uploads", req.GetBucket())
+	}
+	prefix, err := validObjectPrefix(req.GetObjectPrefix())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid object prefix: %s", err)
+	}
+	if key := req.GetKmsKeyName(); key != "" && !kmsKeyNameRE.MatchString(key) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid KMS key name %q", key)
+	}
+	if err := validBuildID(req.GetBuildId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+	ttl, err := uploadTTL(req.GetCredentialsTtlSeconds())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+	objectName := prefix + uuid.NewString()
+	url, fields, err := signURLForUpload(bucket, objectName, req.GetKmsKeyName(), ttl)
 	if err != nil {
 		log.Printf("unable to create signed URL: %s", err)
 		return nil, status.Errorf(codes.Internal, "unable to create signed url")
 	}
+	if id := req.GetBuildId(); id != "" {
+		log.Printf("UploadFile: object %q is for build %q", objectName, id)
+	}
+	bucketName := req.GetBucket()
+	if bucketName == "" {
+		bucketName = s.gceBucketName
+	}
+	s.uploads.record(creds.ID, bucketName, objectName)
 	return &protos.UploadFileResponse{
 		Url:        url,
 		Fields:     fields,
@@ -459,24 +683,101 @@ func (s *Server) UploadFile(ctx context.Context, req *protos.UploadFileRequest)
 	}, nil
 }
 
+const (
+	// defaultUploadTTL is how long upload credentials remain valid
+	// unless the caller asks for longer.
+	defaultUploadTTL = 10 * time.Minute
+	// maxUploadTTL is the longest upload credentials remain valid.
+	maxUploadTTL = 12 * time.Hour
+)
+
+// uploadTTL returns how long upload credentials remain valid given the
+// lifetime in seconds requested by a caller, which is capped at maxUploadTTL.
+func uploadTTL(seconds int64) (time.Duration, error) {
+	switch {
+	case seconds < 0:
+		return 0, fmt.Errorf("invalid credentials TTL %ds", seconds)
+	case seconds == 0:
+		return defaultUploadTTL, nil
+	case seconds > int64(maxUploadTTL/time.Second):
+		return maxUploadTTL, nil
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// maxBuildIDLen is the maximum length of a build ID.
+const maxBuildIDLen = 256
+
+// validBuildID validates a build ID given by a caller. It's opaque, but
+// limited so that it's safe to log.
+func validBuildID(id string) error {
+	if len(id) > maxBuildIDLen {
+		return fmt.Errorf("build ID is longer than %d bytes", maxBuildIDLen)
+	}
+	for _, r := range id {
+		if !unicode.IsPrint(r) {
+			return errors.New("build ID contains unprintable characters")
+		}
+	}
+	return nil
+}
+
+// kmsKeyNameRE matches the resource name of a Cloud KMS key.
+var kmsKeyNameRE = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+// objectPrefixElemRE matches a valid element of an object prefix.
+var objectPrefixElemRE = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
+// validObjectPrefix validates an object name prefix requested by a caller.
+// It returns the prefix with a trailing slash, or the empty string if prefix is empty.
+func validObjectPrefix(prefix string) (string, error) {
+	if prefix == "" {
+		return "", nil
+	}
+	if len(prefix) > 256 {
+		return "", errors.New("too long")
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+	for _, elem := range strings.Split(prefix, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return "", fmt.Errorf("bad path element %q", elem)
+		}
+		if !objectPrefixElemRE.MatchString(elem) {
+			return "", fmt.Errorf("bad characters in %q", elem)
+		}
+	}
+	return prefix + "/", nil
+}
+
 // signURLForUpload generates a signed URL and a set of http Post fields to be used to upload an object to GCS without authenticating.
-func (s *Server) signURLForUpload(object string) (url string, fields map[string]string, err error) {
+// They remain valid for ttl.
+func signURLForUpload(bucket bucketHandle, object, kmsKeyName string, ttl time.Duration) (url string, fields map[string]string, err error) {
 	if object == "" {
 		return "", nil, errors.New("invalid object name")
 	}
-	pv4, err := s.bucket.GenerateSignedPostPolicyV4(object, &storage.PostPolicyV4Options{
-		Expires:  time.Now().Add(10 * time.Minute),
+	opts := &storage.PostPolicyV4Options{
+		Expires:  time.Now().Add(ttl),
 		Insecure: false,
-	})
+	}
+	if kmsKeyName != "" {
+		opts.Conditions = append(opts.Conditions, storage.ConditionStartsWith("$"+kmsKeyField, kmsKeyName))
+	}
+	pv4, err := bucket.GenerateSignedPostPolicyV4(object, opts)
 	if err != nil {
 		return "", nil, fmt.Errorf("unable to generate signed url: %w", err)
 	}
+	if kmsKeyName != "" {
+		pv4.Fields[kmsKeyField] = kmsKeyName
+	}
 	return pv4.URL, pv4.Fields, nil
 }
 
+// kmsKeyField is the form field selecting the Cloud KMS key which encrypts an uploaded object.
+const kmsKeyField = "x-goog-encryption-kms-key-name"
+
 // signURLForDownload generates a signed URL and fields to be used to upload an object to GCS without authenticating.
-func (s *Server) signURLForDownload(object string) (url string, err error) {
-	url, err = s.bucket.SignedURL(object, &storage.SignedURLOptions{
+func signURLForDownload(bucket bucketHandle, object string) (url string, err error) {
+	url, err = bucket.SignedURL(object, &storage.SignedURLOptions{
 		Expires: time.Now().Add(10 * time.Minute),
 		Method:  http.MethodGet,
 		Scheme:  storage.SigningSchemeV4,
@@ -494,23 +795,56 @@ func (s *Server) WriteFileFromURL(ctx context.Context, req *protos.WriteFileFrom
 		log.Printf("WriteTGZFromURL access.IAPFromContext(ctx) = nil, %s", err)
 		return nil, status.Errorf(codes.Unauthenticated, "request does not contain the required authentication")
 	}
-	_, bc, err := s.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
+	parts := req.GetPartUrls()
+	if len(parts) > 0 {
+		if req.GetUrl() != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "URL and part URLs are mutually exclusive")
+		}
+		if req.GetDecrypt() {
+			return nil, status.Errorf(codes.InvalidArgument, "decryption of parts is unsupported")
+		}
+	} else if _, _, ok := s.objectStoreBucket(req.GetUrl()); req.GetWaitForObject() && !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "waiting requires an uploaded object")
+	}
+	if d := req.GetSha256(); len(d) > 0 && len(d) != sha256.Size {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid SHA-256 digest")
+	}
+	if err := validBuildID(req.GetBuildId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
+	session, bc, err := s.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
 	if err != nil {
 		// the helper function returns meaningful GRPC error.
 		return nil, err
 	}
+	var key []byte
+	if req.GetDecrypt() {
+		if key, err = s.decryptionKey(req.GetGomoteId()); err != nil {
+			return nil, err
+		}
+	}
+	filename := req.GetFilename()
+	if req.GetExpandEnv() {
+		filename, err = expandDestination(filename, session.BuilderType)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+		}
+	}
 	var rc io.ReadCloser
+	size := int64(-1) // unknown
 	// objects stored in the gomote staging bucket are only accessible when you have been granted explicit permissions. A builder
 	// requires a signed URL in order to access objects stored in the gomote staging bucket.
-	if onObjectStore(s.gceBucketName, req.GetUrl()) {
-		object, err := objectFromURL(s.gceBucketName, req.GetUrl())
+	if len(parts) > 0 {
+		rc, size, err = s.openParts(ctx, parts, req.GetWaitForObject())
 		if err != nil {
-			return nil, status.Errorf(codes.InvalidArgument, "invalid object URL")
+			return nil, err
 		}
-		rc, err = s.bucket.Object(object).NewReader(ctx)
+	} else if bucketName, bucket, ok := s.objectStoreBucket(req.GetUrl()); ok {
+		or, err := openObject(ctx, bucketName, bucket, req.GetUrl(), req.GetWaitForObject())
 		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to create object reader: %s", err)
+			return nil, err
 		}
+		rc, size = or, or.Attrs.Size
 	} else {
 		httpRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, req.GetUrl(), nil)
 		// TODO(amedee) find sane client defaults, possibly rely on context timeout in request.
@@ -527,15 +861,193 @@ func (s *Server) WriteFileFromURL(ctx context.Context, req *protos.WriteFileFrom
 		if resp.StatusCode != http.StatusOK {
 			return nil, status.Errorf(codes.Aborted, "unable to get file from %q: response code: %d", req.GetUrl(), resp.StatusCode)
 		}
-		rc = resp.Body
+		rc, size = resp.Body, resp.ContentLength
 	}
 	defer rc.Close()
-	if err := bc.Put(ctx, rc, req.GetFilename(), fs.FileMode(req.GetMode())); err != nil {
+	var r io.Reader = rc
+	if key != nil {
+		// The file is opened a chunk at a time as it's written.
+		if size >= 0 {
+			if size, err = sealed.ContentSize(size); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "unable to decrypt file: %s", err)
+			}
+		}
+		if r, err = sealed.NewOpener(r, key); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unable to decrypt file: %s", err)
+		}
+	}
+	h := sha256.New()
+	r = io.TeeReader(r, h)
+	if req.GetModTimeUnixNano() != 0 {
+		// The buildlet can only set modification times when expanding a tarball,
+		// so send the file as a tarball containing just that file.
+		if size < 0 {
+			data, err := io.ReadAll(r)
+			if err != nil {
+				return nil, status.Errorf(codes.Aborted, "failed to get file from URL: %s", err)
+			}
+			r, size = bytes.NewReader(data), int64(len(data))
+		}
+		tgz := fileTarGz(path.Base(filename), fs.FileMode(req.GetMode()), time.Unix(0, req.GetModTimeUnixNano()), size, r)
+		err = bc.PutTar(ctx, tgz, path.Dir(filename))
+		tgz.Close()
+	} else {
+		err = bc.Put(ctx, r, filename, fs.FileMode(req.GetMode()))
+	}
+	if err == nil {
+		// Read anything the write left unread, so that the digest covers
+		// all of the reassembled parts, and a sealed file is opened to its end.
+		_, err = io.Copy(io.Discard, r)
+	}
+	if errors.Is(err, sealed.ErrOpen) {
+		if err := bc.RemoveAll(ctx, filename); err != nil {
+			log.Printf("WriteFileFromURL buildletClient.RemoveAll(ctx, %q) = %s", filename, err)
+		}
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decrypt file: %s", err)
+	}
+	if err != nil {
 		return nil, status.Errorf(codes.Aborted, "failed to send the file to the gomote instance: %s", err)
 	}
+	if want := req.GetSha256(); len(want) > 0 {
+		if got := h.Sum(nil); !bytes.Equal(got, want) {
+			if err := bc.RemoveAll(ctx, filename); err != nil {
+				log.Printf("WriteFileFromURL buildletClient.RemoveAll(ctx, %q) = %s", filename, err)
+			}
+			return nil, status.Errorf(codes.DataLoss, "written file has SHA-256 %x; want %x", got, want)
+		}
+	}
+	source := req.GetUrl()
+	if len(parts) > 0 {
+		source = parts[0]
+	}
+	s.puts.record(req.GetGomoteId(), protos.Put_FILE, source, filename, req.GetBuildId())
 	return &protos.WriteFileFromURLResponse{}, nil
 }
 
+// openObject opens the uploaded object at url in the named bucket, first
+// waiting for it to be readable if wait is set. Errors are gRPC errors.
+func openObject(ctx context.Context, bucketName string, bucket bucketHandle, url string, wait bool) (*storage.Reader, error) {
+	object, err := objectFromURL(bucketName, url)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid object URL")
+	}
+	if wait {
+		if err := waitForObject(ctx, bucket, object); err != nil {
+			return nil, err
+		}
+	}
+	or, err := bucket.Object(object).NewReader(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to create object reader: %s", err)
+	}
+	return or, nil
+}
+
+// openParts returns a reader of the concatenation of the uploaded objects
+// at the part URLs, in order, and its size. Errors are gRPC errors.
+func (s *Server) openParts(ctx context.Context, parts []string, wait bool) (io.ReadCloser, int64, error) {
+	mr := &multiReadCloser{}
+	var readers []io.Reader
+	var size int64
+	for i, url := range parts {
+		bucketName, bucket, ok := s.objectStoreBucket(url)
+		if !ok {
+			mr.Close()
+			return nil, 0, status.Errorf(codes.InvalidArgument, "part %d is not an uploaded object", i)
+		}
+		or, err := openObject(ctx, bucketName, bucket, url, wait)
+		if err != nil {
+			mr.Close()
+			return nil, 0, err
+		}
+		mr.closers = append(mr.closers, or)
+		readers = append(readers, or)
+		size += or.Attrs.Size
+	}
+	mr.Reader = io.MultiReader(readers...)
+	return mr, size, nil
+}
+
+// multiReadCloser reads from a reader made up of others, and closes them
+// all when it's closed.
+type multiReadCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (m *multiReadCloser) Close() error {
+	var err error
+	for _, c := range m.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// fileTarGz returns a .tar.gz containing a single file with the given name, mode,
+// and modification time, whose size bytes of content are read from r.
+// The caller must close the returned reader.
+func fileTarGz(name string, mode fs.FileMode, mtime time.Time, size int64, r io.Reader) io.ReadCloser {
+	if mode.Perm() == 0 {
+		mode = 0644
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		zw := gzip.NewWriter(pw)
+		tw := tar.NewWriter(zw)
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     int64(mode.Perm()),
+			Size:     size,
+			ModTime:  mtime,
+			// Preserve sub-second precision, which other formats round away.
+			Format: tar.FormatPAX,
+		})
+		if err == nil {
+			_, err = io.CopyN(tw, r, size)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+		if err == nil {
+			err = zw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// destinationVars are the variables which may be expanded in a destination path.
+var destinationVars = []string{"WORKDIR", "GO_BUILDER_NAME", "GOOS", "GOARCH"}
+
+// expandDestination expands the ${VAR} and $VAR references in the destination path dst
+// on an instance of the given builder type. Only the variables in destinationVars may be
+// referenced. Since destinations are relative to the work directory, $WORKDIR expands to ".".
+func expandDestination(dst, builderType string) (string, error) {
+	vars := map[string]string{
+		"WORKDIR":         ".",
+		"GO_BUILDER_NAME": builderType,
+	}
+	if conf, ok := dashboard.Builders[builderType]; ok {
+		vars["GOOS"] = conf.GOOS()
+		vars["GOARCH"] = conf.GOARCH()
+	}
+	var missing []string
+	expanded := os.Expand(dst, func(name string) string {
+		v, ok := vars[name]
+		if !ok {
+			missing = append(missing, "$"+name)
+		}
+		return v
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("unable to expand %s in %q; only $%s may be used", strings.Join(missing, ", "), dst, strings.Join(destinationVars, ", $"))
+	}
+	return path.Clean(expanded), nil
+}
+
 // WriteTGZFromURL will instruct the gomote instance to download the tar.gz from the provided URL. The tar.gz file will be unpacked in the work directory
 // relative to the directory provided.
 func (s *Server) WriteTGZFromURL(ctx context.Context, req *protos.WriteTGZFromURLRequest) (*protos.WriteTGZFromURLResponse, error) {
@@ -550,28 +1062,229 @@ func (s *Server) WriteTGZFromURL(ctx context.Context, req *protos.WriteTGZFromUR
 	if req.GetUrl() == "" {
 		return nil, status.Errorf(codes.InvalidArgument, "missing URL")
 	}
+	if req.GetCleanDirectory() && !isSubdirectory(req.GetDirectory()) {
+		// Cleaning anything else would remove the whole work directory.
+		return nil, status.Errorf(codes.InvalidArgument, "cleaning requires a directory below the work directory")
+	}
+	if req.GetRequireEmptyDirectory() {
+		if req.GetDirectory() == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "requiring an empty directory requires a directory")
+		}
+		if req.GetCleanDirectory() {
+			return nil, status.Errorf(codes.InvalidArgument, "cleaning and requiring an empty directory are mutually exclusive")
+		}
+	}
+	if _, _, ok := s.objectStoreBucket(req.GetUrl()); req.GetWaitForObject() && !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "waiting requires an uploaded object")
+	}
+	if err := validBuildID(req.GetBuildId()); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%s", err)
+	}
 	_, bc, err := s.sessionAndClient(ctx, req.GetGomoteId(), creds.ID)
 	if err != nil {
 		// the helper function returns meaningful GRPC error.
 		return nil, err
 	}
+	if req.GetRequireEmptyDirectory() {
+		if err := checkEmptyDirectory(ctx, bc, req.GetDirectory()); err != nil {
+			return nil, err
+		}
+	}
 	url := req.GetUrl()
-	if onObjectStore(s.gceBucketName, url) {
-		object, err := objectFromURL(s.gceBucketName, url)
+	var tgz io.Reader // the opened tar.gz, if it was sealed
+	if req.GetDecrypt() {
+		// The buildlet can't decrypt, so fetch the tar.gz and open it here
+		// a chunk at a time as it's written.
+		bucketName, bucket, ok := s.objectStoreBucket(url)
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "decryption requires an uploaded object")
+		}
+		key, err := s.decryptionKey(req.GetGomoteId())
+		if err != nil {
+			return nil, err
+		}
+		or, err := openObject(ctx, bucketName, bucket, url, req.GetWaitForObject())
+		if err != nil {
+			return nil, err
+		}
+		defer or.Close()
+		if tgz, err = sealed.NewOpener(or, key); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "unable to decrypt tar.gz: %s", err)
+		}
+	} else if bucketName, bucket, ok := s.objectStoreBucket(url); ok {
+		object, err := objectFromURL(bucketName, url)
 		if err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "invalid URL")
 		}
-		url, err = s.signURLForDownload(object)
+		if req.GetWaitForObject() {
+			if err := waitForObject(ctx, bucket, object); err != nil {
+				return nil, err
+			}
+		}
+		url, err = signURLForDownload(bucket, object)
 		if err != nil {
 			return nil, status.Errorf(codes.Aborted, "unable to sign url for download: %s", err)
 		}
 	}
-	if err := bc.PutTarFromURL(ctx, url, req.GetDirectory()); err != nil {
+	if req.GetCleanDirectory() {
+		if err := bc.RemoveAll(ctx, req.GetDirectory()); err != nil {
+			log.Printf("WriteTGZFromURL buildletClient.RemoveAll(ctx, %q) = %s", req.GetDirectory(), err)
+			return nil, status.Errorf(codes.Unknown, "unable to clean directory")
+		}
+	}
+	if tgz != nil {
+		err = bc.PutTar(ctx, tgz, req.GetDirectory())
+	} else {
+		err = bc.PutTarFromURL(ctx, url, req.GetDirectory())
+	}
+	if errors.Is(err, sealed.ErrOpen) {
+		return nil, status.Errorf(codes.InvalidArgument, "unable to decrypt tar.gz: %s", err)
+	}
+	if err != nil {
 		return nil, status.Errorf(codes.FailedPrecondition, "unable to write tar.gz: %s", err)
 	}
+	s.puts.record(req.GetGomoteId(), protos.Put_TGZ, req.GetUrl(), req.GetDirectory(), req.GetBuildId())
 	return &protos.WriteTGZFromURLResponse{}, nil
 }
 
+// isSubdirectory reports whether dir is a relative path to a directory below
+// the work directory of an instance, rather than the work directory itself
+// or a path outside of it.
+func isSubdirectory(dir string) bool {
+	dir = path.Clean(dir)
+	return dir != "." && dir != ".." && !path.IsAbs(dir) && !strings.HasPrefix(dir, "../")
+}
+
+// maxUnexpectedEntries is how many of the entries in a directory which
+// should be empty checkEmptyDirectory lists.
+const maxUnexpectedEntries = 10
+
+// checkEmptyDirectory returns a FailedPrecondition error listing the
+// contents of dir, relative to the work directory of bc, unless it's empty
+// or doesn't exist.
+func checkEmptyDirectory(ctx context.Context, bc buildlet.Client, dir string) error {
+	dir = path.Clean(dir)
+	var names []string
+	if err := bc.ListDir(ctx, dir, buildlet.ListDirOpts{}, func(de buildlet.DirEntry) {
+		names = append(names, de.Name())
+	}); err != nil {
+		// Listing a directory which doesn't exist fails, so tell that
+		// apart from other failures by looking for it.
+		exists, perr := pathExists(ctx, bc, dir)
+		if perr != nil || exists {
+			log.Printf("WriteTGZFromURL buildletClient.ListDir(ctx, %q) = %s", dir, err)
+			return status.Errorf(codes.Unknown, "unable to list directory")
+		}
+		return nil
+	}
+	switch {
+	case len(names) == 0:
+		return nil
+	case len(names) == 1 && names[0] == "":
+		// Listing a file lists just the file itself.
+		return status.Errorf(codes.FailedPrecondition, "%q is not a directory", dir)
+	}
+	listed := names
+	if len(listed) > maxUnexpectedEntries {
+		listed = listed[:maxUnexpectedEntries]
+	}
+	msg := strings.Join(listed, ", ")
+	if n := len(names) - len(listed); n > 0 {
+		msg += fmt.Sprintf(", and %d more", n)
+	}
+	return status.Errorf(codes.FailedPrecondition, "directory %q is not empty: %s", dir, msg)
+}
+
+// pathExists reports whether p, relative to the work directory of bc,
+// exists, by looking for it in a listing of its parent.
+func pathExists(ctx context.Context, bc buildlet.Client, p string) (bool, error) {
+	parent, name := path.Split(p)
+	parent = strings.TrimSuffix(parent, "/")
+	found := false
+	err := bc.ListDir(ctx, parent, buildlet.ListDirOpts{}, func(de buildlet.DirEntry) {
+		if strings.TrimSuffix(de.Name(), "/") == name {
+			found = true
+		}
+	})
+	if err == nil {
+		return found, nil
+	}
+	if parent == "" {
+		// The work directory always exists.
+		return false, err
+	}
+	// The parent may not exist either.
+	if exists, perr := pathExists(ctx, bc, parent); perr != nil {
+		return false, perr
+	} else if exists {
+		return false, err
+	}
+	return false, nil
+}
+
+// objectWaitAttempts and objectWaitBackoff bound how long waitForObject
+// waits: about three seconds in all.
+const (
+	objectWaitAttempts = 5
+	objectWaitBackoff  = 200 * time.Millisecond
+)
+
+// waitForObject waits for a just-uploaded object to become readable, since a
+// write which closely follows an upload can occasionally find it missing.
+func waitForObject(ctx context.Context, bucket bucketHandle, object string) error {
+	backoff := objectWaitBackoff
+	for attempt := 1; ; attempt++ {
+		_, err := bucket.Object(object).Attrs(ctx)
+		switch {
+		case err == nil:
+			return nil
+		case !errors.Is(err, storage.ErrObjectNotExist):
+			return status.Errorf(codes.Internal, "unable to read object attributes: %s", err)
+		case attempt == objectWaitAttempts:
+			return status.Errorf(codes.NotFound, "uploaded object not found")
+		}
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// objectStoreBucket returns the name and handle of the bucket which holds the object referenced by url.
+// It reports false if url does not refer to an object in the transfer bucket or in one of the upload buckets.
+func (s *Server) objectStoreBucket(url string) (string, bucketHandle, bool) {
+	if onObjectStore(s.gceBucketName, url) {
+		return s.gceBucketName, s.bucket, true
+	}
+	for name, bucket := range s.uploadBuckets {
+		if onObjectStore(name, url) {
+			return name, bucket, true
+		}
+	}
+	return "", nil, false
+}
+
+// uploadBucket returns the handle of the named bucket if it's available for uploads.
+// The empty name refers to the transfer bucket.
+func (s *Server) uploadBucket(name string) (bucketHandle, bool) {
+	if name == "" || name == s.gceBucketName {
+		return s.bucket, true
+	}
+	bucket, ok := s.uploadBuckets[name]
+	return bucket, ok
+}
+
+// decryptionKey returns the decryption key set for the gomote instance.
+func (s *Server) decryptionKey(gomoteID string) ([]byte, error) {
+	key, ok := s.keys.get(gomoteID)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "no decryption key is set for the gomote instance")
+	}
+	return key, nil
+}
+
 // session is a helper function that retrieves a session associated with the gomoteID and ownerID.
 func (s *Server) session(gomoteID, ownerID string) (*remote.Session, error) {
 	session, err := s.buildlets.Session(gomoteID)
@@ -633,5 +1346,147 @@ func objectFromURL(bucketName, url string) (string, error) {
 		return "", errors.New("URL not for gomote transfer bucket")
 	}
 	objectName := strings.TrimPrefix(url, fmt.Sprintf("https://storage.googleapis.com/%s/", bucketName))
+	// Drop the query of a signed URL, such as one returned by ReadTGZToURL.
+	if i := strings.IndexByte(objectName, '?'); i >= 0 {
+		objectName = objectName[:i]
+	}
 	return objectName, nil
 }
+
+// uploadOwnerTTL is how long the owner of an uploaded object is
+// remembered: long enough to outlast the credentials for uploading it.
+const uploadOwnerTTL = maxUploadTTL + time.Hour
+
+// uploadOwners records the users who requested the credentials for
+// uploading recent objects, so that only they can act on the objects
+// through the server. The zero value is ready to use.
+type uploadOwners struct {
+	mu     sync.Mutex
+	owners map[uploadedObject]uploadOwner
+	// byExpiry holds the recorded objects in the order their owners
+	// expire, which is the order they were recorded in, so that expired
+	// owners are pruned from the front without scanning the others.
+	byExpiry []uploadedObject
+}
+
+// uploadedObject identifies an uploaded object.
+type uploadedObject struct {
+	bucket, object string
+}
+
+type uploadOwner struct {
+	id      string
+	expires time.Time
+}
+
+// record records that ownerID requested the credentials for uploading
+// object to bucket, and forgets owners recorded more than uploadOwnerTTL ago.
+func (u *uploadOwners) record(ownerID, bucket, object string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := time.Now()
+	if u.owners == nil {
+		u.owners = make(map[uploadedObject]uploadOwner)
+	}
+	for len(u.byExpiry) > 0 {
+		o := u.byExpiry[0]
+		if owner, ok := u.owners[o]; ok && now.Before(owner.expires) {
+			break
+		}
+		delete(u.owners, o)
+		u.byExpiry = u.byExpiry[1:]
+	}
+	o := uploadedObject{bucket, object}
+	u.owners[o] = uploadOwner{id: ownerID, expires: now.Add(uploadOwnerTTL)}
+	u.byExpiry = append(u.byExpiry, o)
+}
+
+// owns reports whether ownerID recently requested the credentials for
+// uploading object to bucket.
+func (u *uploadOwners) owns(ownerID, bucket, object string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	owner, ok := u.owners[uploadedObject{bucket, object}]
+	return ok && owner.id == ownerID && time.Now().Before(owner.expires)
+}
+
+// maxPutsPerInstance is the number of writes recorded for each instance.
+const maxPutsPerInstance = 50
+
+// putLog records the most recent writes to each gomote instance.
+// The zero value is ready to use.
+type putLog struct {
+	mu   sync.Mutex
+	puts map[string][]*protos.Put // keyed by gomote ID, oldest first
+}
+
+// record records a write from the source URL to dst on the gomote instance,
+// tagged with buildID if it's not empty. The query is dropped from the URL,
+// since it may hold credentials.
+func (l *putLog) record(gomoteID string, kind protos.Put_Kind, source, dst, buildID string) {
+	if i := strings.IndexByte(source, '?'); i >= 0 {
+		source = source[:i]
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.puts == nil {
+		l.puts = make(map[string][]*protos.Put)
+	}
+	puts := append(l.puts[gomoteID], &protos.Put{
+		Kind:        kind,
+		Source:      source,
+		Destination: dst,
+		Time:        time.Now().Unix(),
+		BuildId:     buildID,
+	})
+	if len(puts) > maxPutsPerInstance {
+		puts = puts[len(puts)-maxPutsPerInstance:]
+	}
+	l.puts[gomoteID] = puts
+}
+
+// list returns the recorded writes to the gomote instance, oldest first.
+func (l *putLog) list(gomoteID string) []*protos.Put {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]*protos.Put(nil), l.puts[gomoteID]...)
+}
+
+// forget discards the record of writes to the gomote instance.
+func (l *putLog) forget(gomoteID string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.puts, gomoteID)
+}
+
+// decryptionKeys holds the decryption keys set for gomote instances.
+// The zero value is ready to use.
+type decryptionKeys struct {
+	mu   sync.Mutex
+	keys map[string][]byte // keyed by gomote ID
+}
+
+// set sets the decryption key of the gomote instance.
+func (k *decryptionKeys) set(gomoteID string, key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.keys == nil {
+		k.keys = make(map[string][]byte)
+	}
+	k.keys[gomoteID] = append([]byte(nil), key...)
+}
+
+// get returns the decryption key of the gomote instance, if one is set.
+func (k *decryptionKeys) get(gomoteID string) ([]byte, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok := k.keys[gomoteID]
+	return key, ok
+}
+
+// forget discards the decryption key of the gomote instance.
+func (k *decryptionKeys) forget(gomoteID string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, gomoteID)
+}
@@ -8,12 +8,17 @@
 package gomote
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -23,6 +28,7 @@ import (
 	"golang.org/x/build/internal/coordinator/remote"
 	"golang.org/x/build/internal/coordinator/schedule"
 	"golang.org/x/build/internal/gomote/protos"
+	"golang.org/x/build/internal/gomote/sealed"
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/net/nettest"
 	"google.golang.org/grpc"
@@ -33,6 +39,8 @@ import (
 
 const testBucketName = "unit-testing-bucket"
 
+const testUploadBucketName = "unit-testing-upload-bucket"
+
 func fakeGomoteServer(t *testing.T, ctx context.Context) protos.GomoteServiceServer {
 	signer, err := ssh.ParsePrivateKey([]byte(devCertCAPrivate))
 	if err != nil {
@@ -44,17 +52,25 @@ func fakeGomoteServer(t *testing.T, ctx context.Context) protos.GomoteServiceSer
 		gceBucketName:           testBucketName,
 		scheduler:               schedule.NewFake(),
 		sshCertificateAuthority: signer,
+		uploadBuckets: map[string]bucketHandle{
+			testUploadBucketName: &fakeBucketHandler{bucketName: testUploadBucketName},
+		},
 	}
 }
 
 func setupGomoteTest(t *testing.T, ctx context.Context) protos.GomoteServiceClient {
+	return serveGomoteTest(t, fakeGomoteServer(t, ctx))
+}
+
+// serveGomoteTest serves gs and returns a client for it.
+func serveGomoteTest(t *testing.T, gs protos.GomoteServiceServer) protos.GomoteServiceClient {
 	lis, err := nettest.NewLocalListener("tcp")
 	if err != nil {
 		t.Fatalf("unable to create net listener: %s", err)
 	}
 	sopts := access.FakeIAPAuthInterceptorOptions()
 	s := grpc.NewServer(sopts...)
-	protos.RegisterGomoteServiceServer(s, fakeGomoteServer(t, ctx))
+	protos.RegisterGomoteServiceServer(s, gs)
 	go s.Serve(lis)
 
 	// create GRPC client
@@ -309,6 +325,95 @@ func TestInstanceAliveError(t *testing.T) {
 	}
 }
 
+func TestInstanceHealthError(t *testing.T) {
+	// This test will create a gomote instance and attempt to call InstanceHealth.
+	// If overrideID is set to true, the test will use a different gomoteID than
+	// the one created for the test.
+	testCases := []struct {
+		desc       string
+		ctx        context.Context
+		overrideID bool
+		gomoteID   string // Used iff overrideID is true.
+		wantCode   codes.Code
+	}{
+		{
+			desc:     "unauthenticated request",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:       "missing gomote id",
+			ctx:        access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			overrideID: true,
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			desc:       "gomote does not exist",
+			ctx:        access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			overrideID: true,
+			gomoteID:   "xyz",
+			wantCode:   codes.NotFound,
+		},
+		{
+			desc:     "gomote is not owned by caller",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAPWithUser("user-x", "email-y")),
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			client := setupGomoteTest(t, context.Background())
+			gomoteID := mustCreateInstance(t, client, fakeIAP())
+			if tc.overrideID {
+				gomoteID = tc.gomoteID
+			}
+			req := &protos.InstanceHealthRequest{
+				GomoteId: gomoteID,
+			}
+			got, err := client.InstanceHealth(tc.ctx, req)
+			if err != nil && status.Code(err) != tc.wantCode {
+				t.Fatalf("unexpected error: %s; want %s", err, tc.wantCode)
+			}
+			if err == nil {
+				t.Fatalf("client.InstanceHealth(ctx, %v) = %v, nil; want error", req, got)
+			}
+		})
+	}
+}
+
+func TestParseInstanceHealth(t *testing.T) {
+	out := `0.52 0.58 0.59 2/1234 5678
+MemTotal:       16315900 kB
+MemFree:         1000000 kB
+MemAvailable:   12000000 kB
+HugePages_Total:       0
+8
+`
+	got, err := parseInstanceHealth(out)
+	if err != nil {
+		t.Fatalf("parseInstanceHealth(...) = _, %s; want no error", err)
+	}
+	want := &protos.InstanceHealthResponse{
+		LoadAverage:       0.52,
+		NumCpu:            8,
+		MemAvailableBytes: 12000000 << 10,
+		MemTotalBytes:     16315900 << 10,
+	}
+	if diff := cmp.Diff(want, got, protocmp.Transform()); diff != "" {
+		t.Errorf("parseInstanceHealth(...) mismatch (-want, +got):\n%s", diff)
+	}
+	for _, bad := range []string{
+		"",
+		"<this is a song that never ends>",
+		"0.52 0.58 0.59 2/1234 5678\nMemTotal: 1 kB\nMemAvailable: 1 kB\nmany\n",
+		"0.52 0.58 0.59 2/1234 5678\nMemTotal: 1 kB\n8\n",
+	} {
+		if got, err := parseInstanceHealth(bad); err == nil {
+			t.Errorf("parseInstanceHealth(%q) = %v, nil; want error", bad, got)
+		}
+	}
+}
+
 func TestListDirectory(t *testing.T) {
 	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
 	client := setupGomoteTest(t, context.Background())
@@ -415,6 +520,236 @@ func TestListInstance(t *testing.T) {
 	}
 }
 
+func TestListPuts(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "Go is an open source programming language")
+	}))
+	defer ts.Close()
+	if _, err := client.WriteFileFromURL(ctx, &protos.WriteFileFromURLRequest{
+		GomoteId: gomoteID,
+		Url:      ts.URL + "/foo?X-Goog-Signature=secret",
+		Filename: "foo",
+		Mode:     0777,
+	}); err != nil {
+		t.Fatalf("client.WriteFileFromURL(ctx, req) = response, %s; want no error", err)
+	}
+	if _, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
+		GomoteId:  gomoteID,
+		Directory: "go",
+		Url:       `https://go.dev/dl/go1.17.6.linux-amd64.tar.gz`,
+		BuildId:   "ci-1234",
+	}); err != nil {
+		t.Fatalf("client.WriteTGZFromURL(ctx, req) = response, %s; want no error", err)
+	}
+	resp, err := client.ListPuts(ctx, &protos.ListPutsRequest{GomoteId: gomoteID})
+	if err != nil {
+		t.Fatalf("client.ListPuts(ctx, req) = response, %s; want no error", err)
+	}
+	want := []*protos.Put{
+		{Kind: protos.Put_FILE, Source: ts.URL + "/foo", Destination: "foo"},
+		{Kind: protos.Put_TGZ, Source: "https://go.dev/dl/go1.17.6.linux-amd64.tar.gz", Destination: "go", BuildId: "ci-1234"},
+	}
+	if diff := cmp.Diff(want, resp.GetPuts(), protocmp.Transform(), protocmp.IgnoreFields(&protos.Put{}, "time")); diff != "" {
+		t.Errorf("ListPuts() mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestListPutsReusedName(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	server := fakeGomoteServer(t, context.Background()).(*Server)
+	client := serveGomoteTest(t, server)
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	if _, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
+		GomoteId:  gomoteID,
+		Directory: "go",
+		Url:       `https://go.dev/dl/go1.17.6.linux-amd64.tar.gz`,
+	}); err != nil {
+		t.Fatalf("client.WriteTGZFromURL(ctx, req) = response, %s; want no error", err)
+	}
+	// Remove the session the way an expired one is, without DestroyInstance.
+	if err := server.buildlets.DestroySession(gomoteID); err != nil {
+		t.Fatalf("DestroySession(%q) = %s; want no error", gomoteID, err)
+	}
+	if id := mustCreateInstance(t, client, fakeIAP()); id != gomoteID {
+		t.Fatalf("mustCreateInstance() = %q; want reused name %q", id, gomoteID)
+	}
+	resp, err := client.ListPuts(ctx, &protos.ListPutsRequest{GomoteId: gomoteID})
+	if err != nil {
+		t.Fatalf("client.ListPuts(ctx, req) = response, %s; want no error", err)
+	}
+	if puts := resp.GetPuts(); len(puts) != 0 {
+		t.Errorf("ListPuts() = %v; want no puts to the new instance", puts)
+	}
+}
+
+func TestListPutsError(t *testing.T) {
+	// This test will create a gomote instance and attempt to call ListPuts.
+	// If overrideID is set to true, the test will use a different gomoteID than
+	// the one created for the test.
+	testCases := []struct {
+		desc       string
+		ctx        context.Context
+		overrideID bool
+		gomoteID   string // Used iff overrideID is true.
+		wantCode   codes.Code
+	}{
+		{
+			desc:     "unauthenticated request",
+			ctx:      context.Background(),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:       "missing gomote id",
+			ctx:        access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			overrideID: true,
+			gomoteID:   "",
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			desc:       "gomote does not exist",
+			ctx:        access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAPWithUser("foo", "bar")),
+			overrideID: true,
+			gomoteID:   "chucky",
+			wantCode:   codes.NotFound,
+		},
+		{
+			desc:       "wrong gomote id",
+			ctx:        access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAPWithUser("foo", "bar")),
+			overrideID: false,
+			wantCode:   codes.PermissionDenied,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			client := setupGomoteTest(t, context.Background())
+			gomoteID := mustCreateInstance(t, client, fakeIAP())
+			if tc.overrideID {
+				gomoteID = tc.gomoteID
+			}
+			req := &protos.ListPutsRequest{
+				GomoteId: gomoteID,
+			}
+			got, err := client.ListPuts(tc.ctx, req)
+			if err != nil && status.Code(err) != tc.wantCode {
+				t.Fatalf("unexpected error: %s; want %s", err, tc.wantCode)
+			}
+			if err == nil {
+				t.Fatalf("client.ListPuts(ctx, %v) = %v, nil; want error", req, got)
+			}
+		})
+	}
+}
+
+func TestMirrorObjectError(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		ctx      context.Context
+		url      string
+		upload   bool // If set, url is replaced by an object uploaded by fakeIAP().
+		dst      string
+		wantCode codes.Code
+	}{
+		{
+			desc:     "unauthenticated request",
+			ctx:      context.Background(),
+			url:      fmt.Sprintf("https://storage.googleapis.com/%s/foo", testBucketName),
+			dst:      testUploadBucketName,
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "object not uploaded through the server",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      fmt.Sprintf("https://storage.googleapis.com/%s/foo", testBucketName),
+			dst:      testUploadBucketName,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			desc:     "object uploaded by a different user",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAPWithUser("foo", "bar")),
+			upload:   true,
+			dst:      testUploadBucketName,
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			desc:     "not an uploaded object",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "https://go.dev/dl/go1.17.6.linux-amd64.tar.gz",
+			dst:      testUploadBucketName,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "missing destination bucket",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      fmt.Sprintf("https://storage.googleapis.com/%s/foo", testBucketName),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "same bucket",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      fmt.Sprintf("https://storage.googleapis.com/%s/foo", testBucketName),
+			dst:      testBucketName,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "unknown destination bucket",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      fmt.Sprintf("https://storage.googleapis.com/%s/foo", testBucketName),
+			dst:      "some-other-bucket",
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			client := setupGomoteTest(t, context.Background())
+			if tc.upload {
+				ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+				resp, err := client.UploadFile(ctx, &protos.UploadFileRequest{})
+				if err != nil {
+					t.Fatalf("client.UploadFile(ctx, req) = response, %s; want no error", err)
+				}
+				tc.url = fmt.Sprintf("https://storage.googleapis.com/%s/%s", testBucketName, resp.GetObjectName())
+			}
+			req := &protos.MirrorObjectRequest{Url: tc.url, DestinationBucket: tc.dst}
+			got, err := client.MirrorObject(tc.ctx, req)
+			if err != nil && status.Code(err) != tc.wantCode {
+				t.Fatalf("unexpected error: %s; want %s", err, tc.wantCode)
+			}
+			if err == nil {
+				t.Fatalf("client.MirrorObject(ctx, %v) = %v, nil; want error", req, got)
+			}
+		})
+	}
+}
+
+func TestUploadOwners(t *testing.T) {
+	var u uploadOwners
+	u.record("alice", "b", "old")
+	u.record("bob", "b", "new")
+	if !u.owns("alice", "b", "old") || u.owns("bob", "b", "old") || u.owns("alice", "other", "old") {
+		t.Errorf("owners of b/old are wrong")
+	}
+	// Expire the first owner, as if it was recorded uploadOwnerTTL ago.
+	old := uploadedObject{"b", "old"}
+	u.owners[old] = uploadOwner{id: "alice", expires: time.Now().Add(-time.Second)}
+	if u.owns("alice", "b", "old") {
+		t.Errorf("owns() = true for an expired owner; want false")
+	}
+	u.record("alice", "b", "newer")
+	if _, ok := u.owners[old]; ok {
+		t.Errorf("record() kept an expired owner")
+	}
+	want := []uploadedObject{{"b", "new"}, {"b", "newer"}}
+	if diff := cmp.Diff(want, u.byExpiry, cmp.AllowUnexported(uploadedObject{})); diff != "" {
+		t.Errorf("byExpiry mismatch (-want, +got):\n%s", diff)
+	}
+	if !u.owns("bob", "b", "new") || !u.owns("alice", "b", "newer") {
+		t.Errorf("record() dropped an owner which hasn't expired")
+	}
+}
+
 func TestDestroyInstance(t *testing.T) {
 	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
 	client := setupGomoteTest(t, context.Background())
@@ -821,6 +1156,99 @@ func TestUploadFile(t *testing.T) {
 	}
 }
 
+func TestUploadFileBucket(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	_ = mustCreateInstance(t, client, fakeIAP())
+	for _, bucket := range []string{testBucketName, testUploadBucketName} {
+		resp, err := client.UploadFile(ctx, &protos.UploadFileRequest{Bucket: bucket})
+		if err != nil {
+			t.Fatalf("client.UploadFile(ctx, req) = response, %s; want no error", err)
+		}
+		if want := fmt.Sprintf("/%s/", bucket); !strings.Contains(resp.GetUrl(), want) {
+			t.Errorf("client.UploadFile(ctx, req) = %q; want URL containing %q", resp.GetUrl(), want)
+		}
+	}
+}
+
+func TestUploadFileObjectPrefix(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	resp, err := client.UploadFile(ctx, &protos.UploadFileRequest{ObjectPrefix: "gopher/session-1"})
+	if err != nil {
+		t.Fatalf("client.UploadFile(ctx, req) = response, %s; want no error", err)
+	}
+	if got := resp.GetObjectName(); !strings.HasPrefix(got, "gopher/session-1/") {
+		t.Errorf("client.UploadFile(ctx, req) object name = %q; want prefix %q", got, "gopher/session-1/")
+	}
+}
+
+func TestUploadFileKMSKey(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	const key = "projects/p/locations/global/keyRings/r/cryptoKeys/k"
+	resp, err := client.UploadFile(ctx, &protos.UploadFileRequest{KmsKeyName: key})
+	if err != nil {
+		t.Fatalf("client.UploadFile(ctx, req) = response, %s; want no error", err)
+	}
+	if got := resp.GetFields()[kmsKeyField]; got != key {
+		t.Errorf("client.UploadFile(ctx, req) field %q = %q; want %q", kmsKeyField, got, key)
+	}
+}
+
+func TestUploadTTL(t *testing.T) {
+	testCases := []struct {
+		seconds int64
+		want    time.Duration
+		wantErr bool
+	}{
+		{seconds: 0, want: defaultUploadTTL},
+		{seconds: 3600, want: time.Hour},
+		{seconds: int64(maxUploadTTL / time.Second), want: maxUploadTTL},
+		{seconds: 7 * 24 * 3600, want: maxUploadTTL},
+		{seconds: -1, wantErr: true},
+	}
+	for _, tc := range testCases {
+		got, err := uploadTTL(tc.seconds)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("uploadTTL(%d) = %s, %v; want error %t", tc.seconds, got, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("uploadTTL(%d) = %s; want %s", tc.seconds, got, tc.want)
+		}
+	}
+}
+
+func TestValidObjectPrefix(t *testing.T) {
+	testCases := []struct {
+		prefix  string
+		want    string
+		wantErr bool
+	}{
+		{prefix: "", want: ""},
+		{prefix: "gopher", want: "gopher/"},
+		{prefix: "gopher/", want: "gopher/"},
+		{prefix: "gopher/go1.21_rc-1", want: "gopher/go1.21_rc-1/"},
+		{prefix: "/gopher", wantErr: true},
+		{prefix: "gopher//x", wantErr: true},
+		{prefix: "gopher/../x", wantErr: true},
+		{prefix: "gopher x", wantErr: true},
+		{prefix: "gopher?x", wantErr: true},
+		{prefix: strings.Repeat("x", 257), wantErr: true},
+	}
+	for _, tc := range testCases {
+		got, err := validObjectPrefix(tc.prefix)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("validObjectPrefix(%q) = %q, %v; want error %t", tc.prefix, got, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("validObjectPrefix(%q) = %q; want %q", tc.prefix, got, tc.want)
+		}
+	}
+}
+
 func TestUploadFileError(t *testing.T) {
 	// This test will create a gomote instance and attempt to call UploadFile.
 	// If overrideID is set to true, the test will use a different gomoteID than
@@ -830,6 +1258,11 @@ func TestUploadFileError(t *testing.T) {
 		ctx        context.Context
 		overrideID bool
 		filename   string
+		bucket     string
+		prefix     string
+		kmsKey     string
+		buildID    string
+		ttl        int64
 		wantCode   codes.Code
 	}{
 		{
@@ -837,12 +1270,42 @@ func TestUploadFileError(t *testing.T) {
 			ctx:      context.Background(),
 			wantCode: codes.Unauthenticated,
 		},
+		{
+			desc:     "unknown bucket",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			bucket:   "some-other-bucket",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "invalid object prefix",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			prefix:   "../escape",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "invalid KMS key name",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			kmsKey:   "projects/p/keyRings/r",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "invalid build ID",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			buildID:  "ci-1234\n",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "negative credentials TTL",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			ttl:      -60,
+			wantCode: codes.InvalidArgument,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
 			client := setupGomoteTest(t, context.Background())
 			_ = mustCreateInstance(t, client, fakeIAP())
-			req := &protos.UploadFileRequest{}
+			req := &protos.UploadFileRequest{Bucket: tc.bucket, ObjectPrefix: tc.prefix, KmsKeyName: tc.kmsKey, BuildId: tc.buildID, CredentialsTtlSeconds: tc.ttl}
 			got, err := client.UploadFile(tc.ctx, req)
 			if err != nil && status.Code(err) != tc.wantCode {
 				t.Fatalf("unexpected error: %s; want %s", err, tc.wantCode)
@@ -873,6 +1336,210 @@ func TestWriteFileFromURL(t *testing.T) {
 	}
 }
 
+func TestWriteFileFromURLDecrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, sealed.KeySize)
+	sr, err := sealed.NewSealer(strings.NewReader("Go is an open source programming language"), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := io.ReadAll(sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("truncate") {
+			w.Write(payload[:len(payload)-5])
+			return
+		}
+		w.Write(payload)
+	}))
+	defer ts.Close()
+
+	testCases := []struct {
+		desc     string
+		key      []byte // or nil to set no key
+		url      string
+		wantCode codes.Code
+	}{
+		{desc: "correct key", key: key, url: ts.URL, wantCode: codes.OK},
+		{desc: "wrong key", key: bytes.Repeat([]byte{0x24}, sealed.KeySize), url: ts.URL, wantCode: codes.InvalidArgument},
+		{desc: "no key set", url: ts.URL, wantCode: codes.FailedPrecondition},
+		{desc: "truncated file", key: key, url: ts.URL + "/?truncate", wantCode: codes.InvalidArgument},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+			client := setupGomoteTest(t, context.Background())
+			gomoteID := mustCreateInstance(t, client, fakeIAP())
+			if tc.key != nil {
+				if _, err := client.SetDecryptionKey(ctx, &protos.SetDecryptionKeyRequest{GomoteId: gomoteID, Key: tc.key}); err != nil {
+					t.Fatalf("client.SetDecryptionKey(ctx, req) = response, %s; want no error", err)
+				}
+			}
+			_, err := client.WriteFileFromURL(ctx, &protos.WriteFileFromURLRequest{
+				GomoteId: gomoteID,
+				Url:      tc.url,
+				Filename: "foo",
+				Mode:     0644,
+				Decrypt:  true,
+			})
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("client.WriteFileFromURL(ctx, req) = response, %v; want code %s", err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestSetDecryptionKeyError(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		ctx      context.Context
+		key      []byte
+		wantCode codes.Code
+	}{
+		{
+			desc:     "unauthenticated request",
+			ctx:      context.Background(),
+			key:      bytes.Repeat([]byte{0x42}, sealed.KeySize),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			desc:     "short key",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			key:      bytes.Repeat([]byte{0x42}, 16),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "wrong gomote id",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAPWithUser("foo", "bar")),
+			key:      bytes.Repeat([]byte{0x42}, sealed.KeySize),
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			client := setupGomoteTest(t, context.Background())
+			gomoteID := mustCreateInstance(t, client, fakeIAP())
+			req := &protos.SetDecryptionKeyRequest{GomoteId: gomoteID, Key: tc.key}
+			got, err := client.SetDecryptionKey(tc.ctx, req)
+			if err != nil && status.Code(err) != tc.wantCode {
+				t.Fatalf("unexpected error: %s; want %s", err, tc.wantCode)
+			}
+			if err == nil {
+				t.Fatalf("client.SetDecryptionKey(ctx, %v) = %v, nil; want error", req, got)
+			}
+		})
+	}
+}
+
+func TestWriteFileFromURLDigest(t *testing.T) {
+	const content = "Go is an open source programming language"
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, content)
+	}))
+	defer ts.Close()
+
+	sum := sha256.Sum256([]byte(content))
+	testCases := []struct {
+		desc     string
+		sum      []byte
+		wantCode codes.Code
+	}{
+		{desc: "matching digest", sum: sum[:], wantCode: codes.OK},
+		{desc: "mismatched digest", sum: make([]byte, sha256.Size), wantCode: codes.DataLoss},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+			client := setupGomoteTest(t, context.Background())
+			gomoteID := mustCreateInstance(t, client, fakeIAP())
+			_, err := client.WriteFileFromURL(ctx, &protos.WriteFileFromURLRequest{
+				GomoteId: gomoteID,
+				Url:      ts.URL,
+				Filename: "foo",
+				Mode:     0644,
+				Sha256:   tc.sum,
+			})
+			if got := status.Code(err); got != tc.wantCode {
+				t.Fatalf("client.WriteFileFromURL(ctx, req) = response, %v; want code %s", err, tc.wantCode)
+			}
+		})
+	}
+}
+
+func TestWriteFileFromURLExpandEnv(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintln(w, "Go is an open source programming language")
+	}))
+	defer ts.Close()
+	if _, err := client.WriteFileFromURL(ctx, &protos.WriteFileFromURLRequest{
+		GomoteId:  gomoteID,
+		Url:       ts.URL,
+		Filename:  "$WORKDIR/bin/${GOOS}_${GOARCH}/foo",
+		Mode:      0777,
+		ExpandEnv: true,
+	}); err != nil {
+		t.Fatalf("client.WriteFileFromURL(ctx, req) = response, %s; want no error", err)
+	}
+}
+
+func TestExpandDestination(t *testing.T) {
+	testCases := []struct {
+		dst     string
+		want    string
+		wantErr bool
+	}{
+		{dst: "foo", want: "foo"},
+		{dst: "$WORKDIR/foo", want: "foo"},
+		{dst: "bin/${GOOS}_${GOARCH}/foo", want: "bin/linux_amd64/foo"},
+		{dst: "logs/$GO_BUILDER_NAME.log", want: "logs/linux-amd64.log"},
+		{dst: "$HOME/foo", wantErr: true},
+		{dst: "${UNSET}/foo", wantErr: true},
+	}
+	for _, tc := range testCases {
+		got, err := expandDestination(tc.dst, "linux-amd64")
+		if (err != nil) != tc.wantErr {
+			t.Errorf("expandDestination(%q) = %q, %v; want error %t", tc.dst, got, err, tc.wantErr)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("expandDestination(%q) = %q; want %q", tc.dst, got, tc.want)
+		}
+	}
+}
+
+func TestFileTarGz(t *testing.T) {
+	const content = "Go is an open source programming language"
+	mtime := time.Date(2023, time.March, 1, 12, 30, 0, 123456789, time.UTC)
+	tgz := fileTarGz("foo", 0755, mtime, int64(len(content)), strings.NewReader(content))
+	defer tgz.Close()
+	zr, err := gzip.NewReader(tgz)
+	if err != nil {
+		t.Fatalf("gzip.NewReader() = %s", err)
+	}
+	tr := tar.NewReader(zr)
+	h, err := tr.Next()
+	if err != nil {
+		t.Fatalf("tar.Reader.Next() = %s", err)
+	}
+	if h.Name != "foo" || h.Mode != 0755 || !h.ModTime.Equal(mtime) {
+		t.Errorf("header = name %q, mode %o, modtime %s; want name %q, mode %o, modtime %s", h.Name, h.Mode, h.ModTime, "foo", 0755, mtime)
+	}
+	got, err := io.ReadAll(tr)
+	if err != nil {
+		t.Fatalf("reading contents: %s", err)
+	}
+	if string(got) != content {
+		t.Errorf("contents = %q; want %q", got, content)
+	}
+	if _, err := tr.Next(); err != io.EOF {
+		t.Errorf("tar.Reader.Next() = %v; want io.EOF", err)
+	}
+}
+
 func TestWriteFileFromURLError(t *testing.T) {
 	// This test will create a gomote instance and attempt to call TestWriteFileFromURL.
 	// If overrideID is set to true, the test will use a different gomoteID than
@@ -885,6 +1552,10 @@ func TestWriteFileFromURLError(t *testing.T) {
 		url        string
 		filename   string
 		mode       uint32
+		expandEnv  bool
+		wait       bool
+		parts      []string
+		sum        []byte
 		wantCode   codes.Code
 	}{
 		{
@@ -914,6 +1585,45 @@ func TestWriteFileFromURLError(t *testing.T) {
 			url:        "go.dev/dl/1_14.tar.gz",
 			wantCode:   codes.PermissionDenied,
 		},
+		{
+			desc:      "unexpandable variable",
+			ctx:       access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:       "go.dev/dl/1_14.tar.gz",
+			filename:  "$HOME/foo",
+			expandEnv: true,
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			desc:     "waiting without uploaded object",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "go.dev/dl/1_14.tar.gz",
+			filename: "foo",
+			wait:     true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "URL with part URLs",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "go.dev/dl/1_14.tar.gz",
+			filename: "foo",
+			parts:    []string{fmt.Sprintf("https://storage.googleapis.com/%s/part-0", testBucketName)},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "part not uploaded",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			filename: "foo",
+			parts:    []string{"go.dev/dl/1_14.tar.gz"},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "invalid digest",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "go.dev/dl/1_14.tar.gz",
+			filename: "foo",
+			sum:      []byte{1, 2, 3},
+			wantCode: codes.InvalidArgument,
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.desc, func(t *testing.T) {
@@ -923,10 +1633,14 @@ func TestWriteFileFromURLError(t *testing.T) {
 				gomoteID = tc.gomoteID
 			}
 			req := &protos.WriteFileFromURLRequest{
-				GomoteId: gomoteID,
-				Url:      tc.url,
-				Filename: tc.filename,
-				Mode:     0,
+				GomoteId:      gomoteID,
+				Url:           tc.url,
+				Filename:      tc.filename,
+				Mode:          0,
+				ExpandEnv:     tc.expandEnv,
+				WaitForObject: tc.wait,
+				PartUrls:      tc.parts,
+				Sha256:        tc.sum,
 			}
 			got, err := client.WriteFileFromURL(tc.ctx, req)
 			if err != nil && status.Code(err) != tc.wantCode {
@@ -952,6 +1666,20 @@ func TestWriteTGZFromURL(t *testing.T) {
 	}
 }
 
+func TestWriteTGZFromURLCleanDirectory(t *testing.T) {
+	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
+	client := setupGomoteTest(t, context.Background())
+	gomoteID := mustCreateInstance(t, client, fakeIAP())
+	if _, err := client.WriteTGZFromURL(ctx, &protos.WriteTGZFromURLRequest{
+		GomoteId:       gomoteID,
+		Directory:      "foo",
+		Url:            `https://go.dev/dl/go1.17.6.linux-amd64.tar.gz`,
+		CleanDirectory: true,
+	}); err != nil {
+		t.Fatalf("client.WriteTGZFromURL(ctx, req) = response, %s; want no error", err)
+	}
+}
+
 func TestWriteTGZFromURLGomoteStaging(t *testing.T) {
 	ctx := access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP())
 	client := setupGomoteTest(t, context.Background())
@@ -976,6 +1704,11 @@ func TestWriteTGZFromURLError(t *testing.T) {
 		gomoteID   string // Used iff overrideID is true.
 		url        string
 		directory  string
+		clean      bool
+		empty      bool
+		decrypt    bool
+		wait       bool
+		buildID    string
 		wantCode   codes.Code
 	}{
 		{
@@ -995,6 +1728,98 @@ func TestWriteTGZFromURLError(t *testing.T) {
 			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
 			wantCode: codes.InvalidArgument,
 		},
+		{
+			desc:     "clean without directory",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "go.dev/dl/1_14.tar.gz",
+			clean:    true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:      "clean work directory",
+			ctx:       access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:       "go.dev/dl/1_14.tar.gz",
+			directory: ".",
+			clean:     true,
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			desc:      "clean work directory with trailing slash",
+			ctx:       access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:       "go.dev/dl/1_14.tar.gz",
+			directory: "./",
+			clean:     true,
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			desc:      "clean work directory via parent",
+			ctx:       access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:       "go.dev/dl/1_14.tar.gz",
+			directory: "a/..",
+			clean:     true,
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			desc:      "clean outside work directory",
+			ctx:       access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:       "go.dev/dl/1_14.tar.gz",
+			directory: "a/../../b",
+			clean:     true,
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			desc:      "clean absolute directory",
+			ctx:       access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:       "go.dev/dl/1_14.tar.gz",
+			directory: "/tmp",
+			clean:     true,
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			desc:     "require empty without directory",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "go.dev/dl/1_14.tar.gz",
+			empty:    true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:      "clean and require empty",
+			ctx:       access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:       "go.dev/dl/1_14.tar.gz",
+			directory: "foo",
+			clean:     true,
+			empty:     true,
+			wantCode:  codes.InvalidArgument,
+		},
+		{
+			desc:      "directory not empty",
+			ctx:       access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:       "go.dev/dl/1_14.tar.gz",
+			directory: "foo",
+			empty:     true,
+			wantCode:  codes.FailedPrecondition,
+		},
+		{
+			desc:     "decryption without uploaded object",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "go.dev/dl/1_14.tar.gz",
+			decrypt:  true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "waiting without uploaded object",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "go.dev/dl/1_14.tar.gz",
+			wait:     true,
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			desc:     "build ID too long",
+			ctx:      access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAP()),
+			url:      "go.dev/dl/1_14.tar.gz",
+			buildID:  strings.Repeat("x", 257),
+			wantCode: codes.InvalidArgument,
+		},
 		{
 			desc:       "gomote does not exist",
 			ctx:        access.FakeContextWithOutgoingIAPAuth(context.Background(), fakeIAPWithUser("foo", "bar")),
@@ -1019,9 +1844,14 @@ func TestWriteTGZFromURLError(t *testing.T) {
 				gomoteID = tc.gomoteID
 			}
 			req := &protos.WriteTGZFromURLRequest{
-				GomoteId:  gomoteID,
-				Url:       tc.url,
-				Directory: tc.directory,
+				GomoteId:              gomoteID,
+				Url:                   tc.url,
+				Directory:             tc.directory,
+				CleanDirectory:        tc.clean,
+				RequireEmptyDirectory: tc.empty,
+				Decrypt:               tc.decrypt,
+				WaitForObject:         tc.wait,
+				BuildId:               tc.buildID,
 			}
 			got, err := client.WriteTGZFromURL(tc.ctx, req)
 			if err != nil && status.Code(err) != tc.wantCode {
@@ -1054,6 +1884,19 @@ func TestObjectFromURL(t *testing.T) {
 	}
 }
 
+func TestObjectFromSignedURL(t *testing.T) {
+	bucket := "example-bucket"
+	wantObject := "cat.jpeg"
+	url := fmt.Sprintf("https://storage.googleapis.com/%s/%s?X-Goog-Algorithm=GOOG4-RSA-SHA256&X-Goog-Signature=abc", bucket, wantObject)
+	object, err := objectFromURL(bucket, url)
+	if err != nil {
+		t.Fatalf("urlToBucketObject(%q) = %q, %s; want %q, no error", url, object, err, wantObject)
+	}
+	if object != wantObject {
+		t.Fatalf("urlToBucketObject(%q) = %q; want %q", url, object, wantObject)
+	}
+}
+
 func TestObjectFromURLError(t *testing.T) {
 	bucket := "example-bucket"
 	object := "cat.jpeg"
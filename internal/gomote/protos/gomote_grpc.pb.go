@@ -34,15 +34,25 @@ type GomoteServiceClient interface {
 	ExecuteCommand(ctx context.Context, in *ExecuteCommandRequest, opts ...grpc.CallOption) (GomoteService_ExecuteCommandClient, error)
 	// InstanceAlive gives the liveness state of a gomote instance.
 	InstanceAlive(ctx context.Context, in *InstanceAliveRequest, opts ...grpc.CallOption) (*InstanceAliveResponse, error)
+	// InstanceHealth reports the load and available memory of a gomote instance.
+	InstanceHealth(ctx context.Context, in *InstanceHealthRequest, opts ...grpc.CallOption) (*InstanceHealthResponse, error)
 	// ListDirectory lists the contents of a directory on an gomote instance.
 	ListDirectory(ctx context.Context, in *ListDirectoryRequest, opts ...grpc.CallOption) (*ListDirectoryResponse, error)
 	// ListInstances lists all of the live gomote instances owned by the caller.
 	ListInstances(ctx context.Context, in *ListInstancesRequest, opts ...grpc.CallOption) (*ListInstancesResponse, error)
+	// ListPuts lists the files and tarballs most recently written to a gomote instance.
+	ListPuts(ctx context.Context, in *ListPutsRequest, opts ...grpc.CallOption) (*ListPutsResponse, error)
+	// MirrorObject copies an uploaded object to another bucket within GCS, without transferring its contents
+	// through the caller.
+	MirrorObject(ctx context.Context, in *MirrorObjectRequest, opts ...grpc.CallOption) (*MirrorObjectResponse, error)
 	// ReadTGZToURL tars and zips a directory which exists on the gomote instance and returns a URL where it can be
 	// downloaded from.
 	ReadTGZToURL(ctx context.Context, in *ReadTGZToURLRequest, opts ...grpc.CallOption) (*ReadTGZToURLResponse, error)
 	// RemoveFiles removes files or directories from the gomote instance.
 	RemoveFiles(ctx context.Context, in *RemoveFilesRequest, opts ...grpc.CallOption) (*RemoveFilesResponse, error)
+	// SetDecryptionKey sets the key which uploads written to a gomote instance with decrypt set were encrypted with.
+	// The server keeps it for the life of the instance.
+	SetDecryptionKey(ctx context.Context, in *SetDecryptionKeyRequest, opts ...grpc.CallOption) (*SetDecryptionKeyResponse, error)
 	// SignSSHKey signs an SSH public key which can be used to SSH into instances owned by the caller.
 	SignSSHKey(ctx context.Context, in *SignSSHKeyRequest, opts ...grpc.CallOption) (*SignSSHKeyResponse, error)
 	// UploadFile generates a signed URL and associated fields to be used when uploading the object to GCS. Once uploaded
@@ -162,6 +172,15 @@ func (c *gomoteServiceClient) InstanceAlive(ctx context.Context, in *InstanceAli
 	return out, nil
 }
 
+func (c *gomoteServiceClient) InstanceHealth(ctx context.Context, in *InstanceHealthRequest, opts ...grpc.CallOption) (*InstanceHealthResponse, error) {
+	out := new(InstanceHealthResponse)
+	err := c.cc.Invoke(ctx, "/protos.GomoteService/InstanceHealth", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gomoteServiceClient) ListDirectory(ctx context.Context, in *ListDirectoryRequest, opts ...grpc.CallOption) (*ListDirectoryResponse, error) {
 	out := new(ListDirectoryResponse)
 	err := c.cc.Invoke(ctx, "/protos.GomoteService/ListDirectory", in, out, opts...)
@@ -180,6 +199,24 @@ func (c *gomoteServiceClient) ListInstances(ctx context.Context, in *ListInstanc
 	return out, nil
 }
 
+func (c *gomoteServiceClient) ListPuts(ctx context.Context, in *ListPutsRequest, opts ...grpc.CallOption) (*ListPutsResponse, error) {
+	out := new(ListPutsResponse)
+	err := c.cc.Invoke(ctx, "/protos.GomoteService/ListPuts", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gomoteServiceClient) MirrorObject(ctx context.Context, in *MirrorObjectRequest, opts ...grpc.CallOption) (*MirrorObjectResponse, error) {
+	out := new(MirrorObjectResponse)
+	err := c.cc.Invoke(ctx, "/protos.GomoteService/MirrorObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gomoteServiceClient) ReadTGZToURL(ctx context.Context, in *ReadTGZToURLRequest, opts ...grpc.CallOption) (*ReadTGZToURLResponse, error) {
 	out := new(ReadTGZToURLResponse)
 	err := c.cc.Invoke(ctx, "/protos.GomoteService/ReadTGZToURL", in, out, opts...)
@@ -198,6 +235,15 @@ func (c *gomoteServiceClient) RemoveFiles(ctx context.Context, in *RemoveFilesRe
 	return out, nil
 }
 
+func (c *gomoteServiceClient) SetDecryptionKey(ctx context.Context, in *SetDecryptionKeyRequest, opts ...grpc.CallOption) (*SetDecryptionKeyResponse, error) {
+	out := new(SetDecryptionKeyResponse)
+	err := c.cc.Invoke(ctx, "/protos.GomoteService/SetDecryptionKey", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *gomoteServiceClient) SignSSHKey(ctx context.Context, in *SignSSHKeyRequest, opts ...grpc.CallOption) (*SignSSHKeyResponse, error) {
 	out := new(SignSSHKeyResponse)
 	err := c.cc.Invoke(ctx, "/protos.GomoteService/SignSSHKey", in, out, opts...)
@@ -250,15 +296,25 @@ type GomoteServiceServer interface {
 	ExecuteCommand(*ExecuteCommandRequest, GomoteService_ExecuteCommandServer) error
 	// InstanceAlive gives the liveness state of a gomote instance.
 	InstanceAlive(context.Context, *InstanceAliveRequest) (*InstanceAliveResponse, error)
+	// InstanceHealth reports the load and available memory of a gomote instance.
+	InstanceHealth(context.Context, *InstanceHealthRequest) (*InstanceHealthResponse, error)
 	// ListDirectory lists the contents of a directory on an gomote instance.
 	ListDirectory(context.Context, *ListDirectoryRequest) (*ListDirectoryResponse, error)
 	// ListInstances lists all of the live gomote instances owned by the caller.
 	ListInstances(context.Context, *ListInstancesRequest) (*ListInstancesResponse, error)
+	// ListPuts lists the files and tarballs most recently written to a gomote instance.
+	ListPuts(context.Context, *ListPutsRequest) (*ListPutsResponse, error)
+	// MirrorObject copies an uploaded object to another bucket within GCS, without transferring its contents
+	// through the caller.
+	MirrorObject(context.Context, *MirrorObjectRequest) (*MirrorObjectResponse, error)
 	// ReadTGZToURL tars and zips a directory which exists on the gomote instance and returns a URL where it can be
 	// downloaded from.
 	ReadTGZToURL(context.Context, *ReadTGZToURLRequest) (*ReadTGZToURLResponse, error)
 	// RemoveFiles removes files or directories from the gomote instance.
 	RemoveFiles(context.Context, *RemoveFilesRequest) (*RemoveFilesResponse, error)
+	// SetDecryptionKey sets the key which uploads written to a gomote instance with decrypt set were encrypted with.
+	// The server keeps it for the life of the instance.
+	SetDecryptionKey(context.Context, *SetDecryptionKeyRequest) (*SetDecryptionKeyResponse, error)
 	// SignSSHKey signs an SSH public key which can be used to SSH into instances owned by the caller.
 	SignSSHKey(context.Context, *SignSSHKeyRequest) (*SignSSHKeyResponse, error)
 	// UploadFile generates a signed URL and associated fields to be used when uploading the object to GCS. Once uploaded
@@ -293,18 +349,30 @@ func (UnimplementedGomoteServiceServer) ExecuteCommand(*ExecuteCommandRequest, G
 func (UnimplementedGomoteServiceServer) InstanceAlive(context.Context, *InstanceAliveRequest) (*InstanceAliveResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method InstanceAlive not implemented")
 }
+func (UnimplementedGomoteServiceServer) InstanceHealth(context.Context, *InstanceHealthRequest) (*InstanceHealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InstanceHealth not implemented")
+}
 func (UnimplementedGomoteServiceServer) ListDirectory(context.Context, *ListDirectoryRequest) (*ListDirectoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListDirectory not implemented")
 }
 func (UnimplementedGomoteServiceServer) ListInstances(context.Context, *ListInstancesRequest) (*ListInstancesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListInstances not implemented")
 }
+func (UnimplementedGomoteServiceServer) ListPuts(context.Context, *ListPutsRequest) (*ListPutsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPuts not implemented")
+}
+func (UnimplementedGomoteServiceServer) MirrorObject(context.Context, *MirrorObjectRequest) (*MirrorObjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MirrorObject not implemented")
+}
 func (UnimplementedGomoteServiceServer) ReadTGZToURL(context.Context, *ReadTGZToURLRequest) (*ReadTGZToURLResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReadTGZToURL not implemented")
 }
 func (UnimplementedGomoteServiceServer) RemoveFiles(context.Context, *RemoveFilesRequest) (*RemoveFilesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RemoveFiles not implemented")
 }
+func (UnimplementedGomoteServiceServer) SetDecryptionKey(context.Context, *SetDecryptionKeyRequest) (*SetDecryptionKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDecryptionKey not implemented")
+}
 func (UnimplementedGomoteServiceServer) SignSSHKey(context.Context, *SignSSHKeyRequest) (*SignSSHKeyResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SignSSHKey not implemented")
 }
@@ -444,6 +512,24 @@ func _GomoteService_InstanceAlive_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GomoteService_InstanceHealth_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstanceHealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).InstanceHealth(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.GomoteService/InstanceHealth",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).InstanceHealth(ctx, req.(*InstanceHealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GomoteService_ListDirectory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListDirectoryRequest)
 	if err := dec(in); err != nil {
@@ -480,6 +566,42 @@ func _GomoteService_ListInstances_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GomoteService_ListPuts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPutsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).ListPuts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.GomoteService/ListPuts",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).ListPuts(ctx, req.(*ListPutsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _GomoteService_MirrorObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MirrorObjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).MirrorObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.GomoteService/MirrorObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).MirrorObject(ctx, req.(*MirrorObjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GomoteService_ReadTGZToURL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ReadTGZToURLRequest)
 	if err := dec(in); err != nil {
@@ -516,6 +638,24 @@ func _GomoteService_RemoveFiles_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _GomoteService_SetDecryptionKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDecryptionKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GomoteServiceServer).SetDecryptionKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protos.GomoteService/SetDecryptionKey",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GomoteServiceServer).SetDecryptionKey(ctx, req.(*SetDecryptionKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _GomoteService_SignSSHKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SignSSHKeyRequest)
 	if err := dec(in); err != nil {
@@ -611,6 +751,10 @@ var GomoteService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "InstanceAlive",
 			Handler:    _GomoteService_InstanceAlive_Handler,
 		},
+		{
+			MethodName: "InstanceHealth",
+			Handler:    _GomoteService_InstanceHealth_Handler,
+		},
 		{
 			MethodName: "ListDirectory",
 			Handler:    _GomoteService_ListDirectory_Handler,
@@ -619,6 +763,14 @@ var GomoteService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListInstances",
 			Handler:    _GomoteService_ListInstances_Handler,
 		},
+		{
+			MethodName: "ListPuts",
+			Handler:    _GomoteService_ListPuts_Handler,
+		},
+		{
+			MethodName: "MirrorObject",
+			Handler:    _GomoteService_MirrorObject_Handler,
+		},
 		{
 			MethodName: "ReadTGZToURL",
 			Handler:    _GomoteService_ReadTGZToURL_Handler,
@@ -627,6 +779,10 @@ var GomoteService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "RemoveFiles",
 			Handler:    _GomoteService_RemoveFiles_Handler,
 		},
+		{
+			MethodName: "SetDecryptionKey",
+			Handler:    _GomoteService_SetDecryptionKey_Handler,
+		},
 		{
 			MethodName: "SignSSHKey",
 			Handler:    _GomoteService_SignSSHKey_Handler,
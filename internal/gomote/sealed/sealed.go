@@ -0,0 +1,191 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sealed implements the encrypted form of the files gomote uploads
+// with put -encrypt, which the server decrypts as it writes them.
+//
+// A sealed stream is a random salt followed by the contents split into
+// chunks, each sealed with AES-256-GCM under a key derived from the salt
+// and the 32-byte stream key. Every chunk but the last holds ChunkSize bytes
+// of the contents, and the last one is shorter, possibly empty. The nonce of
+// a chunk holds its index and whether it's the last one, so chunks can't be
+// reordered, dropped or truncated without the stream failing to open.
+// Streams are sealed and opened a chunk at a time, so neither needs to hold
+// more than a chunk in memory.
+package sealed
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// KeySize is the size of a stream key.
+	KeySize = 32
+	// ChunkSize is the size of the contents sealed in every chunk but the last.
+	ChunkSize = 64 << 10
+
+	saltSize = 16
+	overhead = 16 // of AES-GCM
+)
+
+// ErrOpen is the error reported when a stream can't be opened, because it
+// was sealed with a different key, or it was modified or truncated.
+var ErrOpen = errors.New("sealed: message authentication failed")
+
+// newAEAD returns the AEAD for the stream with the key and salt.
+func newAEAD(key, salt []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("sealed: key is %d bytes long; want %d", len(key), KeySize)
+	}
+	streamKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, []byte("gomote sealed stream")), streamKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(streamKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// nonce returns the nonce of the chunk with the index.
+func nonce(index uint64, last bool) []byte {
+	n := make([]byte, 12)
+	binary.BigEndian.PutUint64(n[3:11], index)
+	if last {
+		n[11] = 1
+	}
+	return n
+}
+
+// NewSealer returns a reader of the stream sealing the contents of r under key.
+func NewSealer(r io.Reader, key []byte) (io.Reader, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{r: r, aead: aead, buf: make([]byte, ChunkSize+overhead), out: salt}, nil
+}
+
+type sealer struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	index uint64
+	buf   []byte
+	out   []byte // sealed bytes not yet read
+	done  bool   // whether the last chunk has been sealed
+}
+
+func (s *sealer) Read(p []byte) (int, error) {
+	for len(s.out) == 0 {
+		if s.done {
+			return 0, io.EOF
+		}
+		n, err := io.ReadFull(s.r, s.buf[:ChunkSize])
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			// A short chunk is the last one.
+			s.done = true
+		default:
+			return 0, err
+		}
+		s.out = s.aead.Seal(s.buf[:0], nonce(s.index, s.done), s.buf[:n], nil)
+		s.index++
+	}
+	n := copy(p, s.out)
+	s.out = s.out[n:]
+	return n, nil
+}
+
+// NewOpener returns a reader of the contents of the stream sealed under key
+// which r reads. Reading fails with ErrOpen once a chunk fails to open, and
+// nothing of that chunk is returned.
+func NewOpener(r io.Reader, key []byte) (io.Reader, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(r, salt); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, ErrOpen
+		}
+		return nil, err
+	}
+	aead, err := newAEAD(key, salt)
+	if err != nil {
+		return nil, err
+	}
+	return &opener{r: r, aead: aead, buf: make([]byte, ChunkSize+overhead)}, nil
+}
+
+type opener struct {
+	r     io.Reader
+	aead  cipher.AEAD
+	index uint64
+	buf   []byte
+	out   []byte // opened contents not yet read
+	err   error  // sticky; io.EOF after the last chunk
+}
+
+func (o *opener) Read(p []byte) (int, error) {
+	for len(o.out) == 0 {
+		if o.err != nil {
+			return 0, o.err
+		}
+		o.out, o.err = o.next()
+	}
+	n := copy(p, o.out)
+	o.out = o.out[n:]
+	return n, nil
+}
+
+// next opens the next chunk.
+func (o *opener) next() ([]byte, error) {
+	n, err := io.ReadFull(o.r, o.buf)
+	last := false
+	switch err {
+	case nil:
+		// Only a short chunk can be the last one.
+	case io.ErrUnexpectedEOF:
+		last = true
+	case io.EOF:
+		return nil, ErrOpen // truncated after a full chunk
+	default:
+		return nil, err
+	}
+	out, err := o.aead.Open(o.buf[:0], nonce(o.index, last), o.buf[:n], nil)
+	if err != nil {
+		return nil, ErrOpen
+	}
+	o.index++
+	if last {
+		return out, io.EOF
+	}
+	return out, nil
+}
+
+// ContentSize returns the size of the contents of a sealed stream of the
+// given size.
+func ContentSize(sealedSize int64) (int64, error) {
+	body := sealedSize - saltSize
+	if body < overhead {
+		return 0, ErrOpen
+	}
+	full, rem := body/(ChunkSize+overhead), body%(ChunkSize+overhead)
+	if rem < overhead {
+		return 0, ErrOpen
+	}
+	return full*ChunkSize + rem - overhead, nil
+}
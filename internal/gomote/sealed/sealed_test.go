@@ -0,0 +1,104 @@
+// Copyright 2023 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sealed
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+var testKey = bytes.Repeat([]byte{0x42}, KeySize)
+
+func seal(t *testing.T, key, data []byte) []byte {
+	t.Helper()
+	r, err := NewSealer(bytes.NewReader(data), key)
+	if err != nil {
+		t.Fatalf("NewSealer() = %s", err)
+	}
+	sealed, err := io.ReadAll(iotest.OneByteReader(r))
+	if err != nil {
+		t.Fatalf("reading sealed stream: %s", err)
+	}
+	return sealed
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	r, err := NewOpener(bytes.NewReader(sealed), key)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, size := range []int{0, 1, ChunkSize - 1, ChunkSize, ChunkSize + 1, 3 * ChunkSize, 3*ChunkSize + 7} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i * 7)
+		}
+		sealed := seal(t, testKey, data)
+		got, err := open(testKey, sealed)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Errorf("size %d: open() = %d bytes, %v; want the %d sealed bytes", size, len(got), err, size)
+		}
+		if n, err := ContentSize(int64(len(sealed))); err != nil || n != int64(size) {
+			t.Errorf("size %d: ContentSize(%d) = %d, %v; want %d, nil", size, len(sealed), n, err, size)
+		}
+	}
+}
+
+func TestOpenFailure(t *testing.T) {
+	data := bytes.Repeat([]byte("fixture "), ChunkSize/4) // two full chunks and an empty one
+	sealed := seal(t, testKey, data)
+	flipped := append([]byte(nil), sealed...)
+	flipped[len(flipped)-ChunkSize] ^= 1
+	chunk := ChunkSize + overhead
+	swapped := append([]byte(nil), sealed[:saltSize]...)
+	swapped = append(swapped, sealed[saltSize+chunk:saltSize+2*chunk]...)
+	swapped = append(swapped, sealed[saltSize:saltSize+chunk]...)
+	swapped = append(swapped, sealed[saltSize+2*chunk:]...)
+	testCases := []struct {
+		desc   string
+		key    []byte
+		sealed []byte
+	}{
+		{"wrong key", bytes.Repeat([]byte{0x24}, KeySize), sealed},
+		{"modified", testKey, flipped},
+		{"chunks swapped", testKey, swapped},
+		{"last chunk dropped", testKey, sealed[:len(sealed)-overhead]},
+		{"truncated mid-chunk", testKey, sealed[:saltSize+chunk+10]},
+		{"truncated salt", testKey, sealed[:saltSize-1]},
+		{"empty", testKey, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			got, err := open(tc.key, tc.sealed)
+			if !errors.Is(err, ErrOpen) {
+				t.Errorf("open() = %d bytes, %v; want %v", len(got), err, ErrOpen)
+			}
+		})
+	}
+}
+
+func TestKeySize(t *testing.T) {
+	if _, err := NewSealer(bytes.NewReader(nil), testKey[:16]); err == nil {
+		t.Errorf("NewSealer() with a 16-byte key = nil error; want error")
+	}
+	sealed := seal(t, testKey, []byte("fixture"))
+	if _, err := NewOpener(bytes.NewReader(sealed), testKey[:16]); err == nil {
+		t.Errorf("NewOpener() with a 16-byte key = nil error; want error")
+	}
+}
+
+func TestContentSizeInvalid(t *testing.T) {
+	for _, n := range []int64{0, saltSize, saltSize + overhead - 1, saltSize + ChunkSize + overhead} {
+		if got, err := ContentSize(n); err == nil {
+			t.Errorf("ContentSize(%d) = %d, nil; want error", n, got)
+		}
+	}
+}
@@ -28,6 +28,7 @@ type headerContent struct {
 	// For regular files:
 	size    int64
 	content io.ReaderAt
+	stored  bool // content is not compressed
 }
 
 // AddHeader adds a non-regular file to the FileList.
@@ -44,6 +45,22 @@ func (fl *FileList) AddRegular(h *tar.Header, size int64, content io.ReaderAt) {
 	})
 }
 
+// AddRegularStored adds a regular file to the FileList whose content
+// is stored in the .tar.gz without being compressed. It's meant for
+// content which is already compressed, where compressing it again
+// would cost CPU time for no gain.
+//
+// The .tar.gz produced by TarGz then consists of several gzip members,
+// which readers such as compress/gzip treat as a single stream.
+func (fl *FileList) AddRegularStored(h *tar.Header, size int64, content io.ReaderAt) {
+	fl.files = append(fl.files, headerContent{
+		header:  h,
+		size:    size,
+		content: content,
+		stored:  true,
+	})
+}
+
 // TarGz returns an io.ReadCloser of a gzip-compressed tar file
 // containing the contents of the FileList.
 // All Add calls must happen before OpenTarGz is called.
@@ -68,9 +85,18 @@ func (fl *FileList) TarGz() io.ReadCloser {
 }
 
 func (fl *FileList) writeTarGz(w *io.PipeWriter) error {
-	zw := gzip.NewWriter(w)
+	zw := &memberWriter{w: w}
 	tw := tar.NewWriter(zw)
 	for _, f := range fl.files {
+		level := gzip.DefaultCompression
+		if f.stored {
+			level = gzip.NoCompression
+		}
+		// The tar.Writer doesn't buffer, so everything written
+		// from here on goes into a member at this level.
+		if err := zw.setLevel(level); err != nil {
+			return err
+		}
 		if err := tw.WriteHeader(f.header); err != nil {
 			return err
 		}
@@ -87,6 +113,51 @@ func (fl *FileList) writeTarGz(w *io.PipeWriter) error {
 	return zw.Close()
 }
 
+// memberWriter is a gzip writer which can switch compression levels by
+// starting a new gzip member.
+type memberWriter struct {
+	w     io.Writer
+	zw    *gzip.Writer // current member; nil until the first write or setLevel
+	level int
+}
+
+// setLevel ends the current gzip member and starts a new one at the given
+// compression level, unless the current member already uses that level.
+func (m *memberWriter) setLevel(level int) error {
+	if m.zw != nil {
+		if m.level == level {
+			return nil
+		}
+		if err := m.zw.Close(); err != nil {
+			return err
+		}
+	}
+	zw, err := gzip.NewWriterLevel(m.w, level)
+	if err != nil {
+		return err
+	}
+	m.zw, m.level = zw, level
+	return nil
+}
+
+func (m *memberWriter) Write(p []byte) (int, error) {
+	if m.zw == nil {
+		if err := m.setLevel(gzip.DefaultCompression); err != nil {
+			return 0, err
+		}
+	}
+	return m.zw.Write(p)
+}
+
+func (m *memberWriter) Close() error {
+	if m.zw == nil {
+		if err := m.setLevel(gzip.DefaultCompression); err != nil {
+			return err
+		}
+	}
+	return m.zw.Close()
+}
+
 // funcCloser implements io.Closer with a function.
 type funcCloser func() error
 
@@ -81,3 +81,53 @@ func TestFileList(t *testing.T) {
 		t.Errorf("number of entries = %d; want 2", saw)
 	}
 }
+
+func TestFileListStored(t *testing.T) {
+	// Use content which compresses well, so that it's only found
+	// verbatim in the .tar.gz if it's stored.
+	stored := strings.Repeat("already compressed ", 100)
+	fl := new(FileList)
+	fl.AddRegular(tarHeader(t, fileInfo{name: "a.txt", mode: 0644, size: 7}), 7, strings.NewReader("foo bar"))
+	fl.AddRegularStored(tarHeader(t, fileInfo{name: "b.gz", mode: 0644, size: int64(len(stored))}), int64(len(stored)), strings.NewReader(stored))
+	fl.AddRegular(tarHeader(t, fileInfo{name: "c.txt", mode: 0644, size: 3}), 3, strings.NewReader("baz"))
+
+	tgz := fl.TarGz()
+	defer tgz.Close()
+	raw, err := ioutil.ReadAll(tgz)
+	if err != nil {
+		t.Fatalf("reading .tar.gz: %v", err)
+	}
+	if !strings.Contains(string(raw), stored) {
+		t.Errorf("content added with AddRegularStored is not stored verbatim in the .tar.gz")
+	}
+	zr, err := gzip.NewReader(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	tr := tar.NewReader(zr)
+	want := map[string]string{
+		"a.txt": "foo bar",
+		"b.gz":  stored,
+		"c.txt": "baz",
+	}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("tar.Reader.Next: %v", err)
+		}
+		all, err := ioutil.ReadAll(tr)
+		if err != nil {
+			t.Fatalf("Reading %s: %v", h.Name, err)
+		}
+		if string(all) != want[h.Name] {
+			t.Errorf("%s = %q; want %q", h.Name, all, want[h.Name])
+		}
+		delete(want, h.Name)
+	}
+	if len(want) != 0 {
+		t.Errorf("missing entries: %v", want)
+	}
+}